@@ -0,0 +1,557 @@
+package sqltypes
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NullArray is a Postgres array (text[], int[], numeric[]...) of T, which
+// can be a sqltypes type (NullString, NullDecimal...) or any type Scan
+// accepts (string, int64, float64, bool, []byte). Multidimensional arrays
+// are kept flat, in row-major order, with their dimensions in Dims. It is
+// marshaled to JSON as (nested) arrays. Valid = false is NULL.
+type NullArray[T any] struct {
+	Elems []T
+	// Dims are the sizes of each dimension; nil is one dimension of
+	// len(Elems)
+	Dims []int
+	// Lower are the lower bounds of each dimension ("[0:2]={1,2,3}"); nil
+	// is 1, the Postgres default
+	Lower []int
+	Valid bool
+}
+
+// Array returns a valid one dimensional NullArray
+func Array[T any](elems ...T) NullArray[T] {
+	return NullArray[T]{Elems: elems, Valid: true}
+}
+
+// dims returns the dimensions of a, nil if it is empty
+func (a NullArray[T]) dims() []int {
+	if len(a.Dims) == 0 {
+		if len(a.Elems) == 0 {
+			return nil
+		}
+		return []int{len(a.Elems)}
+	}
+	for _, d := range a.Dims {
+		if d == 0 {
+			// there are no empty sub-arrays: {{},{}} is {}
+			return nil
+		}
+	}
+	return a.Dims
+}
+
+func (a NullArray[T]) check() error {
+	dims := a.Dims
+	if len(dims) == 0 {
+		dims = []int{len(a.Elems)}
+	}
+	n := 1
+	for _, d := range dims {
+		if d < 0 {
+			return fmt.Errorf("invalid array dimensions %v", dims)
+		}
+		n *= d
+	}
+	if n != len(a.Elems) {
+		return fmt.Errorf("array dimensions %v don't match %d elements", dims, len(a.Elems))
+	}
+	if a.Lower != nil && len(a.Lower) != len(dims) {
+		return fmt.Errorf("array has %d lower bounds for %d dimensions", len(a.Lower), len(dims))
+	}
+	return nil
+}
+
+// Scan implements the Scanner interface.
+func (a *NullArray[T]) Scan(value interface{}) error {
+	var s string
+	switch v := value.(type) {
+	case nil:
+		a.Elems, a.Dims, a.Lower, a.Valid = nil, nil, nil, false
+		return nil
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	default:
+		return fmt.Errorf("unsupported Scan, storing driver.Value type %T into type %T", value, a)
+	}
+	vals, dims, lower, err := parseArray(s)
+	if err != nil {
+		return err
+	}
+	elems := make([]T, len(vals))
+	for i, v := range vals {
+		if err := scanArrayElem(&elems[i], v); err != nil {
+			return fmt.Errorf("array element %d: %v", i, err)
+		}
+	}
+	a.Elems, a.Dims, a.Lower, a.Valid = elems, nil, nil, true
+	if len(dims) > 1 {
+		a.Dims = dims
+	}
+	for _, l := range lower {
+		if l != 1 {
+			a.Lower = lower
+			break
+		}
+	}
+	return nil
+}
+
+func scanArrayElem(dest interface{}, v *string) error {
+	if v == nil {
+		return convertAssign(dest, nil)
+	}
+	// time.Time has no text Scan
+	if t, ok := dest.(*time.Time); ok {
+		v, err := parseRangeTime(*v)
+		if err != nil {
+			return err
+		}
+		*t = v
+		return nil
+	}
+	// bytea elements are hex encoded
+	if b, ok := dest.(*[]byte); ok && strings.HasPrefix(*v, `\x`) {
+		d, err := hex.DecodeString((*v)[2:])
+		if err != nil {
+			return err
+		}
+		*b = d
+		return nil
+	}
+	return convertAssign(dest, *v)
+}
+
+// parseArray parses the Postgres array text format, returning the elements
+// (nil for NULL) in row-major order, the dimensions and the lower bounds
+func parseArray(s string) ([]*string, []int, []int, error) {
+	orig := s
+	invalid := func(why string) ([]*string, []int, []int, error) {
+		return nil, nil, nil, fmt.Errorf("invalid array '%s': %s", orig, why)
+	}
+	s = strings.TrimSpace(s)
+	// optional dimensions decoration: [1:3][0:1]=
+	var declLower, declUpper []int
+	for strings.HasPrefix(s, "[") {
+		end := strings.IndexByte(s, ']')
+		if end < 0 {
+			return invalid("missing ']'")
+		}
+		b := strings.SplitN(s[1:end], ":", 2)
+		if len(b) != 2 {
+			return invalid("bad dimensions")
+		}
+		lo, err1 := strconv.Atoi(strings.TrimSpace(b[0]))
+		hi, err2 := strconv.Atoi(strings.TrimSpace(b[1]))
+		if err1 != nil || err2 != nil || hi < lo-1 {
+			return invalid("bad dimensions")
+		}
+		declLower, declUpper = append(declLower, lo), append(declUpper, hi)
+		s = strings.TrimSpace(s[end+1:])
+	}
+	if declLower != nil {
+		if !strings.HasPrefix(s, "=") {
+			return invalid("missing '=' after dimensions")
+		}
+		s = strings.TrimSpace(s[1:])
+	}
+	p := arrayParser{s: s, leaf: -1}
+	if err := p.parse(0); err != nil {
+		return invalid(err.Error())
+	}
+	if strings.TrimSpace(p.s[p.i:]) != "" {
+		return invalid("junk after closing brace")
+	}
+	if p.empty {
+		if len(p.vals) > 0 {
+			return invalid("multidimensional arrays must have sub-arrays with matching dimensions")
+		}
+		if declLower != nil {
+			return invalid("dimensions given for an empty array")
+		}
+		return nil, nil, nil, nil
+	}
+	lower := make([]int, len(p.dims))
+	for i := range lower {
+		lower[i] = 1
+	}
+	if declLower != nil {
+		if len(declLower) != len(p.dims) {
+			return invalid("dimensions don't match the value")
+		}
+		for i := range p.dims {
+			if declUpper[i]-declLower[i]+1 != p.dims[i] {
+				return invalid("dimensions don't match the value")
+			}
+		}
+		lower = declLower
+	}
+	return p.vals, p.dims, lower, nil
+}
+
+type arrayParser struct {
+	s    string
+	i    int
+	vals []*string
+	dims []int
+	// leaf is the depth of the elements, -1 until the first one
+	leaf  int
+	empty bool
+}
+
+func (p *arrayParser) skipSpace() {
+	for p.i < len(p.s) && (p.s[p.i] == ' ' || p.s[p.i] == '\t' || p.s[p.i] == '\n' || p.s[p.i] == '\r') {
+		p.i++
+	}
+}
+
+// parse reads a '{...}' at depth
+func (p *arrayParser) parse(depth int) error {
+	p.skipSpace()
+	if p.i >= len(p.s) || p.s[p.i] != '{' {
+		return fmt.Errorf("missing '{'")
+	}
+	p.i++
+	p.skipSpace()
+	if p.i < len(p.s) && p.s[p.i] == '}' {
+		// like Postgres, "{{}}" and "{{},{}}" are the empty array too
+		p.i++
+		p.empty = true
+		return nil
+	}
+	n := 0
+	for {
+		p.skipSpace()
+		if p.i >= len(p.s) {
+			return fmt.Errorf("unexpected end")
+		}
+		if p.s[p.i] == '{' {
+			if p.leaf >= 0 && p.leaf <= depth {
+				return fmt.Errorf("multidimensional arrays must have sub-arrays with matching dimensions")
+			}
+			if err := p.parse(depth + 1); err != nil {
+				return err
+			}
+		} else {
+			if p.leaf >= 0 && p.leaf != depth {
+				return fmt.Errorf("multidimensional arrays must have sub-arrays with matching dimensions")
+			}
+			p.leaf = depth
+			v, err := p.elem()
+			if err != nil {
+				return err
+			}
+			p.vals = append(p.vals, v)
+		}
+		n++
+		p.skipSpace()
+		if p.i >= len(p.s) {
+			return fmt.Errorf("unexpected end")
+		}
+		c := p.s[p.i]
+		p.i++
+		if c == '}' {
+			break
+		}
+		if c != ',' {
+			return fmt.Errorf("unexpected '%c'", c)
+		}
+	}
+	for len(p.dims) <= depth {
+		p.dims = append(p.dims, 0)
+	}
+	if p.dims[depth] == 0 {
+		p.dims[depth] = n
+	} else if p.dims[depth] != n {
+		return fmt.Errorf("multidimensional arrays must have sub-arrays with matching dimensions")
+	}
+	return nil
+}
+
+// elem reads an element; nil is NULL. Only a bare, unescaped NULL is NULL
+// and escaped trailing whitespace is kept, as in Postgres.
+func (p *arrayParser) elem() (*string, error) {
+	var b strings.Builder
+	quoted, escaped := false, false
+	// keep is the length of b up to the last escaped character
+	keep := 0
+	if p.s[p.i] == '"' {
+		quoted = true
+		p.i++
+		for {
+			if p.i >= len(p.s) {
+				return nil, fmt.Errorf("unterminated quote")
+			}
+			c := p.s[p.i]
+			p.i++
+			if c == '"' {
+				break
+			}
+			if c == '\\' {
+				if p.i >= len(p.s) {
+					return nil, fmt.Errorf("unexpected end")
+				}
+				c = p.s[p.i]
+				p.i++
+			}
+			b.WriteByte(c)
+		}
+	} else {
+		for p.i < len(p.s) {
+			c := p.s[p.i]
+			if c == ',' || c == '}' {
+				break
+			}
+			if c == '{' || c == '"' {
+				return nil, fmt.Errorf("unexpected '%c'", c)
+			}
+			p.i++
+			if c == '\\' {
+				if p.i >= len(p.s) {
+					return nil, fmt.Errorf("unexpected end")
+				}
+				b.WriteByte(p.s[p.i])
+				p.i++
+				escaped, keep = true, b.Len()
+				continue
+			}
+			b.WriteByte(c)
+		}
+	}
+	v := b.String()
+	if !quoted {
+		v = v[:keep] + strings.TrimRight(v[keep:], " \t\n\r")
+		if v == "" {
+			return nil, fmt.Errorf("empty element")
+		}
+		if !escaped && strings.EqualFold(v, "NULL") {
+			return nil, nil
+		}
+	}
+	return &v, nil
+}
+
+// Value implements the driver Valuer interface. It writes the Postgres
+// array text format.
+func (a NullArray[T]) Value() (driver.Value, error) {
+	if !a.Valid {
+		return nil, nil
+	}
+	if err := a.check(); err != nil {
+		return nil, err
+	}
+	dims := a.dims()
+	if len(dims) == 0 {
+		return "{}", nil
+	}
+	texts := make([]*string, len(a.Elems))
+	for i := range a.Elems {
+		t, err := arrayElemText(a.Elems[i])
+		if err != nil {
+			return nil, fmt.Errorf("array element %d: %v", i, err)
+		}
+		texts[i] = t
+	}
+	var b strings.Builder
+	if a.Lower != nil {
+		for i, d := range dims {
+			fmt.Fprintf(&b, "[%d:%d]", a.Lower[i], a.Lower[i]+d-1)
+		}
+		b.WriteByte('=')
+	}
+	writeArray(&b, texts, dims)
+	return b.String(), nil
+}
+
+// arrayElemText returns the text of an element; nil is NULL
+func arrayElemText(e interface{}) (*string, error) {
+	var v driver.Value
+	var err error
+	if vr, ok := e.(driver.Valuer); ok {
+		v, err = callValuerValue(vr)
+	} else {
+		v, err = driver.DefaultParameterConverter.ConvertValue(e)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var s string
+	switch x := v.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		s = x
+	case []byte:
+		if x == nil {
+			return nil, nil
+		}
+		s = `\x` + hex.EncodeToString(x)
+	case int64:
+		s = strconv.FormatInt(x, 10)
+	case float64:
+		s = strconv.FormatFloat(x, 'g', -1, 64)
+	case bool:
+		s = "f"
+		if x {
+			s = "t"
+		}
+	case time.Time:
+		s = x.Format("2006-01-02 15:04:05.999999999Z07:00")
+	default:
+		return nil, fmt.Errorf("unsupported type %T", v)
+	}
+	return &s, nil
+}
+
+func writeArray(b *strings.Builder, texts []*string, dims []int) {
+	b.WriteByte('{')
+	if len(dims) == 1 {
+		for i, t := range texts {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			writeArrayElem(b, t)
+		}
+	} else {
+		step := len(texts) / dims[0]
+		for i := 0; i < dims[0]; i++ {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			writeArray(b, texts[i*step:(i+1)*step], dims[1:])
+		}
+	}
+	b.WriteByte('}')
+}
+
+func writeArrayElem(b *strings.Builder, t *string) {
+	if t == nil {
+		b.WriteString("NULL")
+		return
+	}
+	v := *t
+	if v != "" && !strings.EqualFold(v, "NULL") && !strings.ContainsAny(v, "{},\"\\ \t\n\r") {
+		b.WriteString(v)
+		return
+	}
+	b.WriteByte('"')
+	for i := 0; i < len(v); i++ {
+		if v[i] == '"' || v[i] == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(v[i])
+	}
+	b.WriteByte('"')
+}
+
+// MarshalJSON implements json.Marshaler
+func (a NullArray[T]) MarshalJSON() ([]byte, error) {
+	if !a.Valid {
+		return []byte("null"), nil
+	}
+	if err := a.check(); err != nil {
+		return nil, err
+	}
+	dims := a.dims()
+	if len(dims) == 0 {
+		return []byte("[]"), nil
+	}
+	elems := make([][]byte, len(a.Elems))
+	for i := range a.Elems {
+		b, err := json.Marshal(a.Elems[i])
+		if err != nil {
+			return nil, err
+		}
+		elems[i] = b
+	}
+	var b bytes.Buffer
+	writeJSONArray(&b, elems, dims)
+	return b.Bytes(), nil
+}
+
+func writeJSONArray(b *bytes.Buffer, elems [][]byte, dims []int) {
+	b.WriteByte('[')
+	step := len(elems) / dims[0]
+	for i := 0; i < dims[0]; i++ {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		if len(dims) == 1 {
+			b.Write(elems[i])
+		} else {
+			writeJSONArray(b, elems[i*step:(i+1)*step], dims[1:])
+		}
+	}
+	b.WriteByte(']')
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Nested arrays are read as a
+// multidimensional array.
+func (a *NullArray[T]) UnmarshalJSON(v []byte) error {
+	if len(v) == 0 || string(v) == "null" {
+		a.Elems, a.Dims, a.Lower, a.Valid = nil, nil, nil, false
+		return nil
+	}
+	var raw []json.RawMessage
+	var dims []int
+	cur := []json.RawMessage{v}
+	// descend while every element is an array
+	for {
+		var next []json.RawMessage
+		size := -1
+		for _, r := range cur {
+			var sub []json.RawMessage
+			if len(bytes.TrimSpace(r)) == 0 || bytes.TrimSpace(r)[0] != '[' {
+				next = nil
+				size = -2
+				break
+			}
+			if err := json.Unmarshal(r, &sub); err != nil {
+				return err
+			}
+			if size == -1 {
+				size = len(sub)
+			} else if size != len(sub) {
+				return fmt.Errorf("invalid array %s: sub-arrays with different sizes", string(v))
+			}
+			next = append(next, sub...)
+		}
+		if size == -2 {
+			if dims == nil {
+				return fmt.Errorf("invalid array %s", string(v))
+			}
+			break
+		}
+		dims = append(dims, size)
+		cur = next
+		if size == 0 {
+			break
+		}
+	}
+	raw = cur
+	if len(raw) == 0 {
+		a.Elems, a.Dims, a.Lower, a.Valid = []T{}, nil, nil, true
+		return nil
+	}
+	elems := make([]T, len(raw))
+	for i, r := range raw {
+		if err := json.Unmarshal(r, &elems[i]); err != nil {
+			return err
+		}
+	}
+	a.Elems, a.Dims, a.Lower, a.Valid = elems, nil, nil, true
+	if len(dims) > 1 {
+		a.Dims = dims
+	}
+	return nil
+}
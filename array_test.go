@@ -0,0 +1,221 @@
+package sqltypes
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNullArrayScan(t *testing.T) {
+	var s NullArray[NullString]
+	assert.NoError(t, s.Scan([]byte(`{a,"b c","with \"quotes\"","back\\slash",NULL,"NULL",""}`)))
+	assert.True(t, s.Valid)
+	assert.Equal(t, []NullString{"a", "b c", `with "quotes"`, `back\slash`, "", "NULL", ""}, s.Elems)
+	assert.Nil(t, s.Dims)
+	assert.Nil(t, s.Lower)
+	//
+	var i NullArray[int64]
+	assert.NoError(t, i.Scan("{1, 2 ,3}"))
+	assert.Equal(t, []int64{1, 2, 3}, i.Elems)
+	assert.Error(t, i.Scan("{1,NULL}"))
+	assert.Error(t, i.Scan("{1,x}"))
+	//
+	var d NullArray[NullDecimalOpt]
+	assert.NoError(t, d.Scan("{1.50,NULL,-3}"))
+	assert.Equal(t, "1.5", d.Elems[0].String())
+	assert.False(t, d.Elems[1].Valid)
+	assert.Equal(t, "-3", d.Elems[2].String())
+	//
+	var e NullArray[string]
+	assert.NoError(t, e.Scan("{}"))
+	assert.True(t, e.Valid)
+	assert.Empty(t, e.Elems)
+	assert.NoError(t, e.Scan(nil))
+	assert.False(t, e.Valid)
+	assert.Error(t, e.Scan(int64(1)))
+	//
+	var b NullArray[[]byte]
+	assert.NoError(t, b.Scan(`{"\\x0102",NULL}`))
+	assert.Equal(t, []byte{1, 2}, b.Elems[0])
+	assert.Nil(t, b.Elems[1])
+	v, err := b.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"\\x0102",NULL}`, v)
+	b = Array([]byte{}, nil)
+	v, err = b.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"\\x",NULL}`, v)
+	assert.NoError(t, b.Scan(v))
+	assert.Equal(t, []byte{}, b.Elems[0])
+	assert.Nil(t, b.Elems[1])
+	//
+	// an escaped NULL is the string "NULL"; escaped whitespace is kept
+	var n NullArray[string]
+	assert.NoError(t, n.Scan(`{\NULL,N\ULL,a\ , b }`))
+	assert.Equal(t, []string{"NULL", "NULL", "a ", "b"}, n.Elems)
+	assert.Error(t, n.Scan(`{NULL}`))
+	var ns NullArray[NullString]
+	assert.NoError(t, ns.Scan(`{\NULL,null}`))
+	assert.Equal(t, []NullString{"NULL", ""}, ns.Elems)
+	//
+	var bo NullArray[NullBool]
+	assert.NoError(t, bo.Scan("{t,f}"))
+	assert.Equal(t, []NullBool{true, false}, bo.Elems)
+}
+
+func TestNullArrayMultidimensional(t *testing.T) {
+	var a NullArray[int64]
+	assert.NoError(t, a.Scan("{{1,2,3},{4,5,6}}"))
+	assert.Equal(t, []int64{1, 2, 3, 4, 5, 6}, a.Elems)
+	assert.Equal(t, []int{2, 3}, a.Dims)
+	v, err := a.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "{{1,2,3},{4,5,6}}", v)
+	//
+	assert.NoError(t, a.Scan("[0:1][1:2]={{1,2},{3,4}}"))
+	assert.Equal(t, []int{2, 2}, a.Dims)
+	assert.Equal(t, []int{0, 1}, a.Lower)
+	v, err = a.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "[0:1][1:2]={{1,2},{3,4}}", v)
+	//
+	assert.NoError(t, a.Scan("[0:2]={7,8,9}"))
+	assert.Equal(t, []int64{7, 8, 9}, a.Elems)
+	assert.Nil(t, a.Dims)
+	assert.Equal(t, []int{0}, a.Lower)
+	assert.NoError(t, a.Scan("[1:3]={7,8,9}"))
+	assert.Nil(t, a.Lower)
+	// empty sub-arrays are the empty array
+	for _, s := range []string{"{{}}", "{{},{}}", "{ { } }"} {
+		assert.NoError(t, a.Scan(s), s)
+		assert.Empty(t, a.Elems, s)
+		assert.Nil(t, a.Dims, s)
+	}
+	a = NullArray[int64]{Elems: []int64{}, Dims: []int{2, 0}, Valid: true}
+	v, err = a.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "{}", v)
+	assert.NoError(t, a.Scan(v))
+	j, err := json.Marshal(NullArray[int64]{Dims: []int{0, 3}, Lower: []int{0, 0}, Valid: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "[]", string(j))
+	_, err = NullArray[int64]{Elems: []int64{1}, Dims: []int{2, 0}, Valid: true}.Value()
+	assert.Error(t, err)
+	//
+	for _, s := range []string{
+		"{{1,2},{3}}",
+		"{{1,2},3}",
+		"{1,{2,3}}",
+		"{{1},{}}",
+		"{{},{1}}",
+		"[0:1]={1,2,3}",
+		"[0:2][0:0]={1,2,3}",
+		"[0:2]{1,2,3}",
+		"{1,2",
+		"{1,2}x",
+		`{"a}`,
+		"{,}",
+		"1,2",
+	} {
+		assert.Error(t, a.Scan(s), s)
+	}
+}
+
+func TestNullArrayValue(t *testing.T) {
+	v, err := Array[NullString]("a", "b c", `q"`, `\`, "", "null", "{x}").Value()
+	assert.NoError(t, err)
+	assert.Equal(t, `{a,"b c","q\"","\\",NULL,"null","{x}"}`, v)
+	v, err = Array("a", "").Value()
+	assert.NoError(t, err)
+	assert.Equal(t, `{a,""}`, v)
+	v, err = Array(1.5, 2).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, `{1.5,2}`, v)
+	v, err = Array(true, false).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, `{t,f}`, v)
+	v, err = Array(NullDecimalOpt{}, DecimalOpt(decimal.New(15, -1))).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, `{NULL,1.5}`, v)
+	v, err = Array[int]().Value()
+	assert.NoError(t, err)
+	assert.Equal(t, `{}`, v)
+	v, err = NullArray[int]{}.Value()
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+	_, err = NullArray[int]{Elems: []int{1, 2, 3}, Dims: []int{2, 2}, Valid: true}.Value()
+	assert.Error(t, err)
+	// round trip
+	var a NullArray[string]
+	in := Array("a b", `"`, `\\`, "NULL", "x,y", "{}")
+	v, err = in.Value()
+	assert.NoError(t, err)
+	assert.NoError(t, a.Scan(v))
+	assert.Equal(t, in.Elems, a.Elems)
+}
+
+func TestNullArrayTime(t *testing.T) {
+	var a NullArray[NullTime]
+	assert.NoError(t, a.Scan(`{"2020-01-02 03:04:05+00","2020-01-02 03:04:05.5-03:30",NULL}`))
+	assert.True(t, a.Elems[0].T().Equal(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)))
+	assert.True(t, a.Elems[1].T().Equal(time.Date(2020, 1, 2, 6, 34, 5, 5e8, time.UTC)))
+	assert.True(t, a.Elems[2].T().IsZero())
+	// round trip
+	in := Array(NullTime(time.Date(2020, 1, 2, 3, 4, 5, 123456000, time.FixedZone("", -3*3600))), NullTime{})
+	v, err := in.Value()
+	assert.NoError(t, err)
+	assert.NoError(t, a.Scan(v))
+	assert.Len(t, a.Elems, 2)
+	assert.True(t, a.Elems[0].T().Equal(in.Elems[0].T()))
+	assert.True(t, a.Elems[1].T().IsZero())
+	//
+	var tt NullArray[time.Time]
+	assert.NoError(t, tt.Scan(`{"2020-01-02 03:04:05",2020-01-02T03:04:05Z}`))
+	assert.True(t, tt.Elems[0].Equal(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)))
+	assert.True(t, tt.Elems[1].Equal(tt.Elems[0]))
+	v, err = Array(tt.Elems...).Value()
+	assert.NoError(t, err)
+	assert.NoError(t, tt.Scan(v))
+	assert.True(t, tt.Elems[1].Equal(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)))
+}
+
+func TestNullArrayJSON(t *testing.T) {
+	var a NullArray[int64]
+	assert.NoError(t, a.Scan("{{1,2},{3,4}}"))
+	b, err := json.Marshal(a)
+	assert.NoError(t, err)
+	assert.Equal(t, `[[1,2],[3,4]]`, string(b))
+	b, err = json.Marshal(struct {
+		A NullArray[NullString]
+		B NullArray[int]
+		C NullArray[int]
+	}{A: Array[NullString]("x", "y"), C: Array[int]()})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"A":["x","y"],"B":null,"C":[]}`, string(b))
+	//
+	var c NullArray[int64]
+	assert.NoError(t, json.Unmarshal([]byte(`[[1,2,3],[4,5,6]]`), &c))
+	assert.Equal(t, []int64{1, 2, 3, 4, 5, 6}, c.Elems)
+	assert.Equal(t, []int{2, 3}, c.Dims)
+	assert.NoError(t, json.Unmarshal([]byte(`[7]`), &c))
+	assert.Equal(t, []int64{7}, c.Elems)
+	assert.Nil(t, c.Dims)
+	assert.NoError(t, json.Unmarshal([]byte(`[]`), &c))
+	assert.True(t, c.Valid)
+	assert.Empty(t, c.Elems)
+	assert.NoError(t, json.Unmarshal([]byte(`null`), &c))
+	assert.False(t, c.Valid)
+	assert.Error(t, json.Unmarshal([]byte(`[[1],[2,3]]`), &c))
+	assert.Error(t, json.Unmarshal([]byte(`{"a":1}`), &c))
+	//
+	var d NullArray[NullDecimalOpt]
+	assert.NoError(t, json.Unmarshal([]byte(`["1.5",null,2]`), &d))
+	assert.Equal(t, "1.5", d.Elems[0].String())
+	assert.False(t, d.Elems[1].Valid)
+	v, err := d.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, `{1.5,NULL,2}`, v)
+}
@@ -0,0 +1,176 @@
+package sqltypes
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// NullBigInt is an arbitrary precision integer, for NUMERIC(38,0) and
+// DECIMAL(65,0) columns. Its value is written as a decimal string. Valid =
+// false is NULL.
+type NullBigInt struct {
+	Int   *big.Int
+	Valid bool
+	// JSONNumber marshals the integer as a JSON number instead of a string.
+	// Numbers beyond 2^53 lose precision in JavaScript.
+	JSONNumber bool
+}
+
+// B returns a copy of the integer (zero if NULL)
+func (n NullBigInt) B() *big.Int {
+	if !n.Valid || n.Int == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(n.Int)
+}
+
+// String returns the integer in base 10 or "" if NULL
+func (n NullBigInt) String() string {
+	if !n.Valid {
+		return ""
+	}
+	return n.B().String()
+}
+
+// Scan implements the Scanner interface. Fractional values are an error.
+func (n *NullBigInt) Scan(value interface{}) error {
+	var b *big.Int
+	switch v := value.(type) {
+	case nil:
+		n.Int, n.Valid = nil, false
+		return nil
+	case int64:
+		b = big.NewInt(v)
+	case uint64:
+		b = new(big.Int).SetUint64(v)
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) || v != math.Trunc(v) {
+			return fmt.Errorf("invalid integer %v", v)
+		}
+		b, _ = big.NewFloat(v).Int(nil)
+	case []byte:
+		var err error
+		if b, err = parseBigInt(string(v)); err != nil {
+			return err
+		}
+	case string:
+		var err error
+		if b, err = parseBigInt(v); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported Scan, storing driver.Value type %T into type %T", value, n)
+	}
+	n.Int, n.Valid = b, true
+	return nil
+}
+
+// maxBigIntExpDigits is the number of digits an integer written with an
+// exponent may expand to, as in DECIMAL(65,0). It stops "1e1000000" from
+// allocating a million digits.
+const maxBigIntExpDigits = 65
+
+// parseBigInt parses an integer; "100.00" and "1e3" are accepted but "1.5"
+// isn't
+func parseBigInt(s string) (*big.Int, error) {
+	if b, ok := new(big.Int).SetString(s, 10); ok {
+		return b, nil
+	}
+	t := s
+	sign := ""
+	if t != "" && (t[0] == '-' || t[0] == '+') {
+		if t[0] == '-' {
+			sign = "-"
+		}
+		t = t[1:]
+	}
+	exp := 0
+	if i := strings.IndexAny(t, "eE"); i >= 0 {
+		e, err := strconv.Atoi(t[i+1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer '%s'", s)
+		}
+		exp, t = e, t[:i]
+	}
+	digits, frac := t, ""
+	if i := strings.IndexByte(t, '.'); i >= 0 {
+		digits, frac = t[:i], t[i+1:]
+	}
+	if (digits == "" && frac == "") || !isDigits(digits) || !isDigits(frac) {
+		return nil, fmt.Errorf("invalid integer '%s'", s)
+	}
+	// the value is mant * 10^exp
+	mant := strings.TrimLeft(digits+frac, "0")
+	if mant == "" {
+		return new(big.Int), nil
+	}
+	// bound exp before doing arithmetic with it
+	if exp > maxBigIntExpDigits {
+		return nil, fmt.Errorf("invalid integer '%s': more than %d digits", s, maxBigIntExpDigits)
+	}
+	if exp < -len(digits+frac) {
+		return nil, fmt.Errorf("invalid integer '%s': has a fractional part", s)
+	}
+	exp -= len(frac)
+	if exp < 0 {
+		if -exp > len(mant) || strings.TrimRight(mant[len(mant)+exp:], "0") != "" {
+			return nil, fmt.Errorf("invalid integer '%s': has a fractional part", s)
+		}
+		mant = mant[:len(mant)+exp]
+	} else if exp > 0 {
+		if exp > maxBigIntExpDigits-len(mant) {
+			return nil, fmt.Errorf("invalid integer '%s': more than %d digits", s, maxBigIntExpDigits)
+		}
+		mant += strings.Repeat("0", exp)
+	}
+	b, ok := new(big.Int).SetString(sign+mant, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid integer '%s'", s)
+	}
+	return b, nil
+}
+
+// Value implements the driver Valuer interface.
+func (n NullBigInt) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.B().String(), nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (n NullBigInt) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	if n.JSONNumber {
+		return []byte(n.B().String()), nil
+	}
+	return []byte(strconv.Quote(n.B().String())), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts a string or a
+// number.
+func (n *NullBigInt) UnmarshalJSON(v []byte) error {
+	if len(v) == 0 || string(v) == "null" {
+		n.Int, n.Valid = nil, false
+		return nil
+	}
+	s := string(v)
+	if s[0] == '"' {
+		var err error
+		if s, err = strconv.Unquote(s); err != nil {
+			return fmt.Errorf("invalid integer %s", string(v))
+		}
+	}
+	b, err := parseBigInt(s)
+	if err != nil {
+		return err
+	}
+	n.Int, n.Valid = b, true
+	return nil
+}
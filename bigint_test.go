@@ -0,0 +1,86 @@
+package sqltypes
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNullBigIntScan(t *testing.T) {
+	const huge = "123456789012345678901234567890123456789"
+	var n NullBigInt
+	assert.NoError(t, n.Scan([]byte(huge)))
+	assert.True(t, n.Valid)
+	assert.Equal(t, huge, n.String())
+	v, err := n.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, huge, v)
+	//
+	for in, out := range map[interface{}]string{
+		int64(-42):             "-42",
+		uint64(math.MaxUint64): "18446744073709551615",
+		float64(1e20):          "100000000000000000000",
+		"-7":                   "-7",
+		"100.000":              "100",
+		"1e3":                  "1000",
+		"-1.5E2":               "-150",
+		"1200e-2":              "12",
+		"0e-1000000":           "0",
+	} {
+		assert.NoError(t, n.Scan(in), "%v", in)
+		assert.Equal(t, out, n.String(), "%v", in)
+	}
+	for _, in := range []interface{}{1.5, math.NaN(), math.Inf(1), "1.5", "abc", "", ".", "1e", "1.2.3", "-", true} {
+		assert.Error(t, n.Scan(in), "%v", in)
+	}
+	assert.NoError(t, n.Scan(nil))
+	assert.False(t, n.Valid)
+	v, err = n.Value()
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+	assert.Equal(t, "0", n.B().String())
+}
+
+func TestNullBigIntExponent(t *testing.T) {
+	var n NullBigInt
+	assert.Error(t, n.Scan("1e1000000"))
+	assert.Error(t, n.Scan("1e-1000000"))
+	assert.Error(t, n.Scan("1e99999999999999999999"))
+	assert.Error(t, json.Unmarshal([]byte(`1e1000000`), &n))
+	for _, in := range []string{"1e-9223372036854775808", "1.5e-9223372036854775808", "1e9223372036854775807", "10e-2", "1.00e-3"} {
+		assert.Error(t, n.Scan(in), in)
+		assert.Error(t, json.Unmarshal([]byte(in), &n), in)
+	}
+	assert.NoError(t, n.Scan("0e-9223372036854775808"))
+	assert.Equal(t, "0", n.String())
+	assert.NoError(t, n.Scan("1200e-2"))
+	assert.Equal(t, "12", n.String())
+	assert.NoError(t, n.Scan("1e64"))
+	assert.Equal(t, 65, len(n.String()))
+	assert.Error(t, n.Scan("1e65"))
+}
+
+func TestNullBigIntJSON(t *testing.T) {
+	b, _ := new(big.Int).SetString("98765432109876543210", 10)
+	n := BigInt(b)
+	j, err := json.Marshal(n)
+	assert.NoError(t, err)
+	assert.Equal(t, `"98765432109876543210"`, string(j))
+	n.JSONNumber = true
+	j, err = json.Marshal(n)
+	assert.NoError(t, err)
+	assert.Equal(t, `98765432109876543210`, string(j))
+	j, err = json.Marshal(NullBigInt{JSONNumber: true})
+	assert.NoError(t, err)
+	assert.Equal(t, `null`, string(j))
+	//
+	var s struct{ A, B, C NullBigInt }
+	assert.NoError(t, json.Unmarshal([]byte(`{"A":"12345678901234567890123","B":12345678901234567890123,"C":null}`), &s))
+	assert.Equal(t, "12345678901234567890123", s.A.String())
+	assert.Equal(t, "12345678901234567890123", s.B.String())
+	assert.False(t, s.C.Valid)
+	assert.Error(t, json.Unmarshal([]byte(`{"A":1.5}`), &s))
+}
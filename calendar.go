@@ -0,0 +1,380 @@
+package sqltypes
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Calendar holds the weekend days and holidays used to compute business days.
+//
+// Holidays are declared with a spec, one of:
+//
+//	MM-DD           every year (12-25)
+//	YYYY-MM-DD      a single date (2019-11-20)
+//	easter+N        N days after (or before, easter-N) Easter Sunday
+//
+// optionally followed by "/FROM-[TO]" to restrict the years the holiday
+// applies ("11-20/2024-").
+//
+// The text format read by ParseCalendar has one holiday per line as
+// "<spec> <name>", an optional "weekend <day>..." line and '#' comments:
+//
+//	weekend sat sun
+//	12-25 Natal
+//	easter-47 Carnaval
+//
+// The JSON format is
+//
+//	{"weekend":["sat","sun"],"holidays":[{"date":"12-25","name":"Natal"}]}
+type Calendar struct {
+	weekend [7]bool
+	rules   []holidayRule
+}
+
+type holidayRule struct {
+	spec   string
+	name   string
+	date   NullDate // single date
+	month  int      // annual date
+	day    int
+	easter bool // day is an offset from Easter
+	from   int
+	to     int
+}
+
+// NewCalendar returns a Calendar with the given weekend days and no holidays
+func NewCalendar(weekend ...time.Weekday) *Calendar {
+	c := &Calendar{}
+	for _, wd := range weekend {
+		c.weekend[wd] = true
+	}
+	return c
+}
+
+// BrazilCalendar returns a Calendar with Saturday and Sunday weekends and the
+// Brazilian national (and banking) holidays.
+func BrazilCalendar() *Calendar {
+	c := NewCalendar(time.Saturday, time.Sunday)
+	for _, h := range [][2]string{
+		{"01-01", "Confraternização Universal"},
+		{"easter-48", "Carnaval"},
+		{"easter-47", "Carnaval"},
+		{"easter-2", "Sexta-feira Santa"},
+		{"04-21", "Tiradentes"},
+		{"05-01", "Dia do Trabalho"},
+		{"easter+60", "Corpus Christi"},
+		{"09-07", "Independência do Brasil"},
+		{"10-12", "Nossa Senhora Aparecida"},
+		{"11-02", "Finados"},
+		{"11-15", "Proclamação da República"},
+		{"11-20/2024-", "Dia Nacional de Zumbi e da Consciência Negra"},
+		{"12-25", "Natal"},
+	} {
+		if err := c.AddHoliday(h[0], h[1]); err != nil {
+			panic(err)
+		}
+	}
+	return c
+}
+
+// ParseCalendar reads a Calendar in the text format
+func ParseCalendar(r io.Reader) (*Calendar, error) {
+	c := &Calendar{}
+	s := bufio.NewScanner(r)
+	n := 0
+	for s.Scan() {
+		n++
+		line := s.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == "weekend" {
+			if err := c.setWeekend(fields[1:]); err != nil {
+				return nil, fmt.Errorf("calendar line %d: %v", n, err)
+			}
+			continue
+		}
+		if err := c.AddHoliday(fields[0], strings.Join(fields[1:], " ")); err != nil {
+			return nil, fmt.Errorf("calendar line %d: %v", n, err)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+func (c *Calendar) setWeekend(days []string) error {
+	c.weekend = [7]bool{}
+	for _, d := range days {
+		k := strings.ToLower(d)
+		if len(k) > 3 {
+			k = k[:3]
+		}
+		wd, ok := weekdayNames[k]
+		if !ok {
+			return fmt.Errorf("invalid weekday '%s'", d)
+		}
+		c.weekend[wd] = true
+	}
+	return nil
+}
+
+// AddHoliday adds a holiday rule (see Calendar for the spec format)
+func (c *Calendar) AddHoliday(spec, name string) error {
+	r := holidayRule{spec: spec, name: name}
+	s := spec
+	if i := strings.IndexByte(s, '/'); i >= 0 {
+		from, to, ok := parseYearRange(s[i+1:])
+		if !ok {
+			return fmt.Errorf("invalid holiday '%s'", spec)
+		}
+		r.from, r.to = from, to
+		s = s[:i]
+	}
+	switch {
+	case strings.HasPrefix(s, "easter"):
+		r.easter = true
+		if off := s[len("easter"):]; off != "" {
+			n, err := strconv.Atoi(off)
+			if err != nil {
+				return fmt.Errorf("invalid holiday '%s'", spec)
+			}
+			r.day = n
+		}
+	case len(s) == len("01-02"):
+		var d NullDate
+		if d.strscan("2000-"+s) != nil || s[2] != '-' {
+			return fmt.Errorf("invalid holiday '%s'", spec)
+		}
+		r.month, r.day = int(d.Month()), d.Day()
+	default:
+		if r.date.strscan(s) != nil || r.date.IsZero() {
+			return fmt.Errorf("invalid holiday '%s'", spec)
+		}
+	}
+	c.rules = append(c.rules, r)
+	return nil
+}
+
+func parseYearRange(s string) (from, to int, ok bool) {
+	parts := strings.Split(s, "-")
+	if len(parts) > 2 {
+		return 0, 0, false
+	}
+	var err error
+	if parts[0] != "" {
+		if from, err = strconv.Atoi(parts[0]); err != nil {
+			return 0, 0, false
+		}
+	}
+	if len(parts) == 1 {
+		return from, from, true
+	}
+	if parts[1] != "" {
+		if to, err = strconv.Atoi(parts[1]); err != nil {
+			return 0, 0, false
+		}
+	}
+	return from, to, true
+}
+
+// on returns the date of the holiday in year (NULL if it doesn't apply)
+func (r holidayRule) on(year int) NullDate {
+	if (r.from != 0 && year < r.from) || (r.to != 0 && year > r.to) {
+		return NullDate{}
+	}
+	switch {
+	case !r.date.IsZero():
+		if r.date.Year() != year {
+			return NullDate{}
+		}
+		return r.date
+	case r.easter:
+		return Easter(year).AddDays(r.day)
+	}
+	if !isValidDate(year, r.month, r.day) {
+		// February 29
+		return NullDate{}
+	}
+	return packDate(year, r.month, r.day)
+}
+
+// Easter returns Easter Sunday of year (Gregorian calendar)
+func Easter(year int) NullDate {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return packDate(year, month, day)
+}
+
+// Holiday returns the name of the holiday on d and whether d is a holiday
+func (c *Calendar) Holiday(d NullDate) (string, bool) {
+	if d.IsZero() {
+		return "", false
+	}
+	// easter offsets can cross into the next or previous year
+	for _, r := range c.rules {
+		if r.easter {
+			if r.on(d.Year()-1) == d || r.on(d.Year()+1) == d {
+				return r.name, true
+			}
+		}
+		if r.on(d.Year()) == d {
+			return r.name, true
+		}
+	}
+	return "", false
+}
+
+// IsHoliday reports whether d is a holiday
+func (c *Calendar) IsHoliday(d NullDate) bool {
+	_, ok := c.Holiday(d)
+	return ok
+}
+
+// IsWeekend reports whether d falls on a weekend day
+func (c *Calendar) IsWeekend(d NullDate) bool {
+	return !d.IsZero() && c.weekend[d.Weekday()]
+}
+
+// IsBusinessDay reports whether d is neither a weekend day nor a holiday
+func (c *Calendar) IsBusinessDay(d NullDate) bool {
+	return !d.IsZero() && !c.IsWeekend(d) && !c.IsHoliday(d)
+}
+
+// NextBusinessDay returns the first business day after d
+func (c *Calendar) NextBusinessDay(d NullDate) NullDate {
+	return c.step(d, 1)
+}
+
+// PreviousBusinessDay returns the last business day before d
+func (c *Calendar) PreviousBusinessDay(d NullDate) NullDate {
+	return c.step(d, -1)
+}
+
+func (c *Calendar) step(d NullDate, dir int) NullDate {
+	if c.weekend == [7]bool{true, true, true, true, true, true, true} {
+		return NullDate{}
+	}
+	for {
+		d = d.AddDays(dir)
+		if d.IsZero() || c.IsBusinessDay(d) {
+			return d
+		}
+	}
+}
+
+// AddBusinessDays moves n business days from d (backwards if n < 0). If n is
+// 0, d is rolled forward to the next business day unless it already is one.
+func (c *Calendar) AddBusinessDays(d NullDate, n int) NullDate {
+	if d.IsZero() {
+		return d
+	}
+	if n == 0 {
+		if c.IsBusinessDay(d) {
+			return d
+		}
+		return c.NextBusinessDay(d)
+	}
+	dir := 1
+	if n < 0 {
+		dir, n = -1, -n
+	}
+	for ; n > 0 && !d.IsZero(); n-- {
+		d = c.step(d, dir)
+	}
+	return d
+}
+
+// BusinessDaysBetween returns the number of business days after a up to and
+// including b (negative if b is before a)
+func (c *Calendar) BusinessDaysBetween(a, b NullDate) int {
+	if a.IsZero() || b.IsZero() {
+		return 0
+	}
+	sign := 1
+	if b.Before(a) {
+		a, b, sign = b, a, -1
+	}
+	n := 0
+	for d := a.AddDays(1); !d.IsZero() && !d.After(b); d = d.AddDays(1) {
+		if c.IsBusinessDay(d) {
+			n++
+		}
+	}
+	return sign * n
+}
+
+type calendarJSON struct {
+	Weekend  []string      `json:"weekend"`
+	Holidays []holidayJSON `json:"holidays"`
+}
+
+type holidayJSON struct {
+	Date string `json:"date"`
+	Name string `json:"name"`
+}
+
+// MarshalJSON implements json.Marshaler
+func (c *Calendar) MarshalJSON() ([]byte, error) {
+	cj := calendarJSON{
+		Weekend:  []string{},
+		Holidays: []holidayJSON{},
+	}
+	for wd, ok := range c.weekend {
+		if ok {
+			cj.Weekend = append(cj.Weekend, strings.ToLower(time.Weekday(wd).String()[:3]))
+		}
+	}
+	for _, r := range c.rules {
+		cj.Holidays = append(cj.Holidays, holidayJSON{Date: r.spec, Name: r.name})
+	}
+	return json.Marshal(cj)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (c *Calendar) UnmarshalJSON(v []byte) error {
+	cj := calendarJSON{}
+	if err := json.Unmarshal(v, &cj); err != nil {
+		return err
+	}
+	nc := Calendar{}
+	if err := nc.setWeekend(cj.Weekend); err != nil {
+		return err
+	}
+	for _, h := range cj.Holidays {
+		if err := nc.AddHoliday(h.Date, h.Name); err != nil {
+			return err
+		}
+	}
+	*c = nc
+	return nil
+}
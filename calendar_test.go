@@ -0,0 +1,70 @@
+package sqltypes
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEaster(t *testing.T) {
+	assert.Equal(t, Date(2019, time.April, 21), Easter(2019))
+	assert.Equal(t, Date(2020, time.April, 12), Easter(2020))
+	assert.Equal(t, Date(2024, time.March, 31), Easter(2024))
+	assert.Equal(t, Date(2038, time.April, 25), Easter(2038))
+}
+
+func TestBrazilCalendar(t *testing.T) {
+	c := BrazilCalendar()
+	name, ok := c.Holiday(Date(2020, time.February, 25))
+	assert.True(t, ok)
+	assert.Equal(t, "Carnaval", name)
+	assert.True(t, c.IsHoliday(Date(2019, time.June, 20)))
+	assert.True(t, c.IsHoliday(Date(2024, time.November, 20)))
+	assert.False(t, c.IsHoliday(Date(2019, time.November, 20)))
+	assert.False(t, c.IsBusinessDay(Date(2019, time.July, 20)))
+	assert.True(t, c.IsBusinessDay(Date(2019, time.July, 22)))
+	// friday before carnival
+	d := Date(2020, time.February, 21)
+	assert.Equal(t, Date(2020, time.February, 26), c.NextBusinessDay(d))
+	assert.Equal(t, Date(2020, time.February, 27), c.AddBusinessDays(d, 2))
+	assert.Equal(t, d, c.AddBusinessDays(Date(2020, time.February, 26), -1))
+	assert.Equal(t, Date(2020, time.February, 26), c.AddBusinessDays(Date(2020, time.February, 22), 0))
+	assert.Equal(t, 2, c.BusinessDaysBetween(d, Date(2020, time.February, 27)))
+	assert.Equal(t, -2, c.BusinessDaysBetween(Date(2020, time.February, 27), d))
+}
+
+func TestParseCalendar(t *testing.T) {
+	c, err := ParseCalendar(strings.NewReader(`
+# a friday/saturday weekend
+weekend fri sat
+12-25 Christmas
+2019-11-20 One off
+easter-2 Good Friday # comment
+`))
+	assert.NoError(t, err)
+	assert.True(t, c.IsWeekend(Date(2019, time.July, 19)))
+	assert.False(t, c.IsWeekend(Date(2019, time.July, 21)))
+	assert.True(t, c.IsHoliday(Date(2019, time.November, 20)))
+	assert.False(t, c.IsHoliday(Date(2020, time.November, 20)))
+	name, _ := c.Holiday(Date(2019, time.April, 19))
+	assert.Equal(t, "Good Friday", name)
+	_, err = ParseCalendar(strings.NewReader("13-01 Nope"))
+	assert.Error(t, err)
+	_, err = ParseCalendar(strings.NewReader("weekend someday"))
+	assert.Error(t, err)
+}
+
+func TestCalendarJSON(t *testing.T) {
+	c := &Calendar{}
+	assert.NoError(t, json.Unmarshal([]byte(`{"weekend":["sat","sunday"],"holidays":[{"date":"01-01","name":"New Year"},{"date":"easter+60","name":"Corpus Christi"}]}`), c))
+	assert.True(t, c.IsHoliday(Date(2020, time.January, 1)))
+	assert.True(t, c.IsHoliday(Date(2020, time.June, 11)))
+	assert.True(t, c.IsWeekend(Date(2020, time.June, 14)))
+	bb, err := json.Marshal(c)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"weekend":["sun","sat"],"holidays":[{"date":"01-01","name":"New Year"},{"date":"easter+60","name":"Corpus Christi"}]}`, string(bb))
+	assert.Error(t, json.Unmarshal([]byte(`{"holidays":[{"date":"easterish"}]}`), c))
+}
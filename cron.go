@@ -0,0 +1,342 @@
+package sqltypes
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NullCron is a cron expression: the standard 5 fields (minute hour
+// day-of-month month day-of-week), 6 fields with a leading seconds field, or
+// one of the macros @yearly, @annually, @monthly, @weekly, @daily, @midnight
+// and @hourly. Invalid expressions are rejected on Scan and UnmarshalJSON.
+// Valid = false is NULL.
+type NullCron struct {
+	Expr string
+	// Location is the timezone the expression is evaluated in. When nil, the
+	// location of the time given to Next or Prev is used.
+	Location *time.Location
+	Valid    bool
+
+	sched *cronSchedule
+}
+
+type cronSchedule struct {
+	second, minute, hour, dom, month, dow uint64
+	// domStar and dowStar are set when the field is '*' or '?'; when both
+	// day fields are restricted a day matching either is a match
+	domStar, dowStar bool
+}
+
+type cronField struct {
+	min, max int
+	names    []string
+}
+
+var cronFields = []cronField{
+	{0, 59, nil}, // second
+	{0, 59, nil}, // minute
+	{0, 23, nil}, // hour
+	{1, 31, nil}, // day of month
+	{1, 12, []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}},
+	{0, 7, []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}}, // 7 is Sunday
+}
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseCron parses and validates a cron expression
+func ParseCron(expr string) (NullCron, error) {
+	s, err := parseCron(expr)
+	if err != nil {
+		return NullCron{}, err
+	}
+	return NullCron{Expr: strings.TrimSpace(expr), Valid: true, sched: s}, nil
+}
+
+func parseCron(expr string) (*cronSchedule, error) {
+	e := strings.TrimSpace(expr)
+	if strings.HasPrefix(e, "@") {
+		m, ok := cronMacros[strings.ToLower(e)]
+		if !ok {
+			return nil, fmt.Errorf("invalid cron expression '%s': unknown macro", expr)
+		}
+		e = m
+	}
+	fields := strings.Fields(e)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("invalid cron expression '%s': expected 5 or 6 fields, got %d", expr, len(fields))
+	}
+	var bits [6]uint64
+	for i, f := range fields {
+		b, err := parseCronField(f, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression '%s': %v", expr, err)
+		}
+		bits[i] = b
+	}
+	// day of week 7 is Sunday
+	if bits[5]&(1<<7) != 0 {
+		bits[5] = bits[5]&^(1<<7) | 1
+	}
+	return &cronSchedule{
+		second:  bits[0],
+		minute:  bits[1],
+		hour:    bits[2],
+		dom:     bits[3],
+		month:   bits[4],
+		dow:     bits[5],
+		domStar: fields[3] == "*" || fields[3] == "?",
+		dowStar: fields[5] == "*" || fields[5] == "?",
+	}, nil
+}
+
+// parseCronField parses a comma separated list of "*", "?", "a", "a-b",
+// "*/n", "a/n" and "a-b/n"
+func parseCronField(s string, f cronField) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(s, ",") {
+		step := 1
+		if i := strings.IndexByte(part, '/'); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("invalid step in '%s'", part)
+			}
+			step = n
+			part = part[:i]
+		}
+		lo, hi := f.min, f.max
+		switch {
+		case part == "*" || part == "?":
+		case strings.Contains(part, "-"):
+			r := strings.SplitN(part, "-", 2)
+			var err error
+			if lo, err = cronValue(r[0], f); err != nil {
+				return 0, err
+			}
+			if hi, err = cronValue(r[1], f); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("invalid range '%s'", part)
+			}
+		default:
+			v, err := cronValue(part, f)
+			if err != nil {
+				return 0, err
+			}
+			lo = v
+			if step == 1 {
+				hi = v
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func cronValue(s string, f cronField) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(s, name) {
+			return i + f.min, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid value '%s' (%d-%d)", s, f.min, f.max)
+	}
+	return v, nil
+}
+
+func (c NullCron) schedule() *cronSchedule {
+	if c.sched != nil {
+		return c.sched
+	}
+	s, err := parseCron(c.Expr)
+	if err != nil {
+		return nil
+	}
+	return s
+}
+
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// cronSearchYears bounds the search of Next and Prev for expressions that
+// never match (0 0 30 2 *)
+const cronSearchYears = 5
+
+// Next returns the first time after after matching the expression, or a
+// zero NullTime if there is none (or c is NULL or invalid).
+func (c NullCron) Next(after NullTime) NullTime {
+	s := c.schedule()
+	if !c.Valid || s == nil {
+		return NullTime{}
+	}
+	t := after.T()
+	if c.Location != nil {
+		t = t.In(c.Location)
+	}
+	loc := t.Location()
+	t = t.Truncate(time.Second).Add(time.Second)
+	limit := t.Year() + cronSearchYears
+	for t.Year() <= limit {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = hourStart(t).Add(time.Hour)
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Truncate(time.Minute).Add(time.Minute)
+			continue
+		}
+		if s.second&(1<<uint(t.Second())) == 0 {
+			t = t.Add(time.Second)
+			continue
+		}
+		return NullTime(t)
+	}
+	return NullTime{}
+}
+
+// Prev returns the last time before before matching the expression, or a
+// zero NullTime if there is none.
+func (c NullCron) Prev(before NullTime) NullTime {
+	s := c.schedule()
+	if !c.Valid || s == nil {
+		return NullTime{}
+	}
+	t := before.T()
+	if c.Location != nil {
+		t = t.In(c.Location)
+	}
+	loc := t.Location()
+	if t.Truncate(time.Second).Equal(t) {
+		t = t.Add(-time.Second)
+	} else {
+		t = t.Truncate(time.Second)
+	}
+	limit := t.Year() - cronSearchYears
+	for t.Year() >= limit {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc).Add(-time.Second)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc).Add(-time.Second)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = hourStart(t).Add(-time.Second)
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Truncate(time.Minute).Add(-time.Second)
+			continue
+		}
+		if s.second&(1<<uint(t.Second())) == 0 {
+			t = t.Add(-time.Second)
+			continue
+		}
+		return NullTime(t)
+	}
+	return NullTime{}
+}
+
+// hourStart returns the start of the wall clock hour of t. It is computed in
+// absolute time, as time.Date picks either 01:00 when the clocks fall back.
+func hourStart(t time.Time) time.Time {
+	return t.Add(-time.Duration(t.Minute())*time.Minute - time.Duration(t.Second())*time.Second - time.Duration(t.Nanosecond()))
+}
+
+// String returns the expression or "" if NULL
+func (c NullCron) String() string {
+	if !c.Valid {
+		return ""
+	}
+	return c.Expr
+}
+
+// Scan implements the Scanner interface.
+func (c *NullCron) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		c.Expr, c.Valid, c.sched = "", false, nil
+		return nil
+	case []byte:
+		return c.parse(string(v))
+	case string:
+		return c.parse(v)
+	}
+	return fmt.Errorf("unsupported Scan, storing driver.Value type %T into type %T", value, c)
+}
+
+func (c *NullCron) parse(s string) error {
+	nc, err := ParseCron(s)
+	if err != nil {
+		return err
+	}
+	c.Expr, c.Valid, c.sched = nc.Expr, true, nc.sched
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (c NullCron) Value() (driver.Value, error) {
+	if !c.Valid {
+		return nil, nil
+	}
+	if c.schedule() == nil {
+		_, err := parseCron(c.Expr)
+		return nil, err
+	}
+	return c.Expr, nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (c NullCron) MarshalJSON() ([]byte, error) {
+	if !c.Valid {
+		return []byte("null"), nil
+	}
+	return []byte(strconv.Quote(c.Expr)), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (c *NullCron) UnmarshalJSON(v []byte) error {
+	if len(v) == 0 || string(v) == "null" {
+		c.Expr, c.Valid, c.sched = "", false, nil
+		return nil
+	}
+	s, err := strconv.Unquote(string(v))
+	if err != nil {
+		return fmt.Errorf("invalid cron expression %s", string(v))
+	}
+	return c.parse(s)
+}
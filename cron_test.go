@@ -0,0 +1,112 @@
+package sqltypes
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNullCronScan(t *testing.T) {
+	var c NullCron
+	assert.NoError(t, c.Scan("*/15 9-17 * * MON-FRI"))
+	assert.True(t, c.Valid)
+	assert.NoError(t, c.Scan([]byte("30 0 12 1,15 * ?")))
+	assert.NoError(t, c.Scan("@daily"))
+	v, err := c.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "@daily", v)
+	assert.Error(t, c.Scan("* * * *"))
+	assert.Error(t, c.Scan("60 * * * *"))
+	assert.Error(t, c.Scan("* * 0 * *"))
+	assert.Error(t, c.Scan("* * * * FUNDAY"))
+	assert.Error(t, c.Scan("5-1 * * * *"))
+	assert.Error(t, c.Scan("*/0 * * * *"))
+	assert.Error(t, c.Scan("@sometimes"))
+	assert.NoError(t, c.Scan(nil))
+	assert.False(t, c.Valid)
+	_, err = NullCron{Expr: "bad", Valid: true}.Value()
+	assert.Error(t, err)
+}
+
+func TestNullCronNext(t *testing.T) {
+	at := func(s string) NullTime {
+		tt, err := time.Parse("2006-01-02 15:04:05", s)
+		assert.NoError(t, err)
+		return NullTime(tt)
+	}
+	cases := []struct {
+		expr, from, next, prev string
+	}{
+		{"*/15 9-17 * * MON-FRI", "2020-01-03 17:50:00", "2020-01-06 09:00:00", "2020-01-03 17:45:00"},
+		{"@monthly", "2020-01-31 12:00:00", "2020-02-01 00:00:00", "2020-01-01 00:00:00"},
+		{"0 0 29 2 *", "2020-03-01 00:00:00", "2024-02-29 00:00:00", "2020-02-29 00:00:00"},
+		{"30 */10 * * * *", "2020-01-01 00:00:30", "2020-01-01 00:10:30", "2019-12-31 23:50:30"},
+		{"0 12 13 * 5", "2020-03-01 00:00:00", "2020-03-06 12:00:00", "2020-02-28 12:00:00"},
+		{"0 0 * * 7", "2020-01-01 00:00:00", "2020-01-05 00:00:00", "2019-12-29 00:00:00"},
+	}
+	for _, tc := range cases {
+		c, err := ParseCron(tc.expr)
+		assert.NoError(t, err)
+		assert.Equal(t, at(tc.next).T(), c.Next(at(tc.from)).T(), tc.expr)
+		assert.Equal(t, at(tc.prev).T(), c.Prev(at(tc.from)).T(), tc.expr)
+	}
+	c, _ := ParseCron("0 0 30 2 *")
+	assert.True(t, c.Next(at("2020-01-01 00:00:00")).T().IsZero())
+}
+
+func TestNullCronLocation(t *testing.T) {
+	loc := time.FixedZone("BRT", -3*3600)
+	c, err := ParseCron("0 9 * * *")
+	assert.NoError(t, err)
+	c.Location = loc
+	n := c.Next(NullTime(time.Date(2020, 1, 1, 11, 0, 0, 0, time.UTC)))
+	assert.Equal(t, time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC), n.T().UTC())
+	assert.Equal(t, loc, n.T().Location())
+	// unparsed literal
+	c2 := NullCron{Expr: "0 9 * * *", Valid: true}
+	assert.Equal(t, time.Date(2020, 1, 2, 9, 0, 0, 0, time.UTC), c2.Next(NullTime(time.Date(2020, 1, 1, 11, 0, 0, 0, time.UTC))).T())
+}
+
+func TestNullCronDST(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no tzdata")
+	}
+	c, err := ParseCron("0 3 * * *")
+	assert.NoError(t, err)
+	c.Location = ny
+	// fall back: 01:00-02:00 happens twice
+	n := c.Next(NullTime(time.Date(2020, 11, 1, 0, 30, 0, 0, ny)))
+	assert.Equal(t, time.Date(2020, 11, 1, 8, 0, 0, 0, time.UTC), n.T().UTC())
+	p := c.Prev(NullTime(time.Date(2020, 11, 1, 2, 30, 0, 0, ny)))
+	assert.Equal(t, time.Date(2020, 10, 31, 7, 0, 0, 0, time.UTC), p.T().UTC())
+	c2, _ := ParseCron("30 1 * * *")
+	c2.Location = ny
+	n = c2.Next(NullTime(time.Date(2020, 11, 1, 5, 45, 0, 0, time.UTC)))
+	assert.Equal(t, time.Date(2020, 11, 1, 6, 30, 0, 0, time.UTC), n.T().UTC())
+	// spring forward: 02:00-03:00 doesn't happen
+	n = c.Next(NullTime(time.Date(2020, 3, 8, 0, 30, 0, 0, ny)))
+	assert.Equal(t, time.Date(2020, 3, 8, 7, 0, 0, 0, time.UTC), n.T().UTC())
+	p = c.Prev(NullTime(time.Date(2020, 3, 8, 4, 0, 0, 0, ny)))
+	assert.Equal(t, time.Date(2020, 3, 8, 7, 0, 0, 0, time.UTC), p.T().UTC())
+	// half hour offsets
+	c.Location = time.FixedZone("IST", 5*3600+1800)
+	n = c.Next(NullTime(time.Date(2020, 1, 1, 0, 10, 0, 0, c.Location)))
+	assert.Equal(t, time.Date(2020, 1, 1, 3, 0, 0, 0, c.Location), n.T())
+}
+
+func TestNullCronJSON(t *testing.T) {
+	str := struct {
+		C NullCron `json:"c"`
+	}{}
+	assert.NoError(t, json.Unmarshal([]byte(`{"c":"@hourly"}`), &str))
+	assert.True(t, str.C.Valid)
+	bb, err := json.Marshal(str)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"c":"@hourly"}`, string(bb))
+	assert.Error(t, json.Unmarshal([]byte(`{"c":"61 * * * *"}`), &str))
+	assert.NoError(t, json.Unmarshal([]byte(`{"c":null}`), &str))
+	assert.False(t, str.C.Valid)
+}
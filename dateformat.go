@@ -0,0 +1,481 @@
+package sqltypes
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// DateOrder is the order of the numeric fields of a date
+type DateOrder int
+
+const (
+	// YMD is year-month-day (ISO 8601)
+	YMD DateOrder = iota
+	// DMY is day-month-year (pt-BR, en-GB)
+	DMY
+	// MDY is month-day-year (en-US)
+	MDY
+)
+
+// DateLocale describes how a locale writes dates
+type DateLocale struct {
+	Name string
+	// Order is the order of numeric dates ("09/03/2018")
+	Order DateOrder
+	// Separators are the accepted separators of numeric dates
+	Separators string
+	// Pivot splits two-digit years: years below it are 20yy, the others
+	// 19yy. Zero means 70.
+	Pivot int
+	// Layouts are tried by ParseDateLocale when the date isn't numeric
+	Layouts       []string
+	Months        [12]string
+	ShortMonths   [12]string
+	Weekdays      [7]string
+	ShortWeekdays [7]string
+}
+
+var (
+	enMonths        = [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"}
+	enShortMonths   = [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"}
+	enWeekdays      = [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}
+	enShortWeekdays = [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}
+	ptMonths        = [12]string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"}
+	ptShortMonths   = [12]string{"jan", "fev", "mar", "abr", "mai", "jun", "jul", "ago", "set", "out", "nov", "dez"}
+	ptWeekdays      = [7]string{"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"}
+	ptShortWeekdays = [7]string{"dom", "seg", "ter", "qua", "qui", "sex", "sáb"}
+)
+
+var dateLocales = struct {
+	sync.RWMutex
+	m map[string]*DateLocale
+}{m: make(map[string]*DateLocale)}
+
+func init() {
+	en := DateLocale{
+		Separators:    "/-.",
+		Months:        enMonths,
+		ShortMonths:   enShortMonths,
+		Weekdays:      enWeekdays,
+		ShortWeekdays: enShortWeekdays,
+	}
+	pt := DateLocale{
+		Name:          "pt",
+		Order:         DMY,
+		Separators:    "/-.",
+		Layouts:       []string{"2 de January de 2006", "2 January 2006", "2 Jan 2006"},
+		Months:        ptMonths,
+		ShortMonths:   ptShortMonths,
+		Weekdays:      ptWeekdays,
+		ShortWeekdays: ptShortWeekdays,
+	}
+	enUS := en
+	enUS.Name, enUS.Order = "en-US", MDY
+	enUS.Layouts = []string{"January 2, 2006", "Jan 2, 2006", "2 January 2006"}
+	enGB := en
+	enGB.Name, enGB.Order = "en-GB", DMY
+	enGB.Layouts = []string{"2 January 2006", "2 Jan 2006", "January 2, 2006"}
+	enX := enUS
+	enX.Name = "en"
+	ptBR := pt
+	ptBR.Name = "pt-BR"
+	ptPT := pt
+	ptPT.Name = "pt-PT"
+	for _, l := range []DateLocale{enX, enUS, enGB, pt, ptBR, ptPT} {
+		l := l
+		RegisterDateLocale(&l)
+	}
+}
+
+func localeKey(name string) string {
+	return strings.ToLower(strings.Replace(name, "_", "-", -1))
+}
+
+// RegisterDateLocale adds (or replaces) a date locale
+func RegisterDateLocale(l *DateLocale) {
+	dateLocales.Lock()
+	dateLocales.m[localeKey(l.Name)] = l
+	dateLocales.Unlock()
+}
+
+// DateLocaleFor returns the locale named name ("pt-BR", "pt_BR"), falling
+// back to its language ("pt").
+func DateLocaleFor(name string) (*DateLocale, bool) {
+	k := localeKey(name)
+	dateLocales.RLock()
+	defer dateLocales.RUnlock()
+	if l, ok := dateLocales.m[k]; ok {
+		return l, true
+	}
+	if i := strings.IndexByte(k, '-'); i > 0 {
+		l, ok := dateLocales.m[k[:i]]
+		return l, ok
+	}
+	return nil, false
+}
+
+func (l *DateLocale) pivot() int {
+	if l == nil || l.Pivot == 0 {
+		return 70
+	}
+	return l.Pivot
+}
+
+// date layout tokens
+const (
+	tokLiteral = iota
+	tokYear
+	tokYear2
+	tokMonth
+	tokMonth2
+	tokMonthName
+	tokMonthShort
+	tokDay
+	tokDay2
+	tokDaySpace
+	tokYearDay
+	tokWeekday
+	tokWeekdayShort
+	tokWeekdayNum  // %u: 1-7, Monday = 1
+	tokWeekdayNum0 // %w: 0-6, Sunday = 0
+)
+
+type dateToken struct {
+	kind int
+	lit  string
+}
+
+var goDateTokens = []struct {
+	s    string
+	kind int
+}{
+	{"2006", tokYear},
+	{"January", tokMonthName},
+	{"Jan", tokMonthShort},
+	{"Monday", tokWeekday},
+	{"Mon", tokWeekdayShort},
+	{"002", tokYearDay},
+	{"01", tokMonth2},
+	{"02", tokDay2},
+	{"06", tokYear2},
+	{"_2", tokDaySpace},
+	{"1", tokMonth},
+	{"2", tokDay},
+}
+
+var strftimeTokens = map[byte][]dateToken{
+	'Y': {{kind: tokYear}},
+	'y': {{kind: tokYear2}},
+	'm': {{kind: tokMonth2}},
+	'd': {{kind: tokDay2}},
+	'e': {{kind: tokDaySpace}},
+	'B': {{kind: tokMonthName}},
+	'b': {{kind: tokMonthShort}},
+	'h': {{kind: tokMonthShort}},
+	'A': {{kind: tokWeekday}},
+	'a': {{kind: tokWeekdayShort}},
+	'j': {{kind: tokYearDay}},
+	'u': {{kind: tokWeekdayNum}},
+	'w': {{kind: tokWeekdayNum0}},
+	'F': {{kind: tokYear}, {lit: "-"}, {kind: tokMonth2}, {lit: "-"}, {kind: tokDay2}},
+	'D': {{kind: tokMonth2}, {lit: "/"}, {kind: tokDay2}, {lit: "/"}, {kind: tokYear2}},
+	'%': {{lit: "%"}},
+}
+
+// tokenizeDateLayout splits a strftime ("%d/%m/%Y") or Go ("02/01/2006")
+// layout into tokens. Layouts with a '%' are strftime.
+func tokenizeDateLayout(layout string) ([]dateToken, error) {
+	var toks []dateToken
+	lit := func(s string) {
+		if n := len(toks); n > 0 && toks[n-1].kind == tokLiteral {
+			toks[n-1].lit += s
+			return
+		}
+		toks = append(toks, dateToken{lit: s})
+	}
+	if strings.IndexByte(layout, '%') >= 0 {
+		for i := 0; i < len(layout); i++ {
+			if layout[i] != '%' {
+				lit(layout[i : i+1])
+				continue
+			}
+			i++
+			if i == len(layout) {
+				return nil, fmt.Errorf("invalid date layout '%s'", layout)
+			}
+			tt, ok := strftimeTokens[layout[i]]
+			if !ok {
+				return nil, fmt.Errorf("invalid date layout '%s': unsupported %%%c", layout, layout[i])
+			}
+			for _, t := range tt {
+				if t.kind == tokLiteral {
+					lit(t.lit)
+				} else {
+					toks = append(toks, t)
+				}
+			}
+		}
+		return toks, nil
+	}
+next:
+	for i := 0; i < len(layout); {
+		for _, t := range goDateTokens {
+			if strings.HasPrefix(layout[i:], t.s) {
+				toks = append(toks, dateToken{kind: t.kind})
+				i += len(t.s)
+				continue next
+			}
+		}
+		_, n := utf8.DecodeRuneInString(layout[i:])
+		lit(layout[i : i+n])
+		i += n
+	}
+	return toks, nil
+}
+
+// Format formats d with a strftime ("%d/%m/%Y") or Go ("02/01/2006") layout
+// using English names. It returns "" if d is NULL.
+func (d NullDate) Format(layout string) string {
+	s, _ := d.FormatLocale(layout, "en")
+	return s
+}
+
+// FormatLocale formats d like Format with the month and weekday names of
+// locale.
+func (d NullDate) FormatLocale(layout, locale string) (string, error) {
+	l, ok := DateLocaleFor(locale)
+	if !ok {
+		return "", fmt.Errorf("unknown date locale '%s'", locale)
+	}
+	toks, err := tokenizeDateLayout(layout)
+	if err != nil {
+		return "", err
+	}
+	if d.IsZero() {
+		return "", nil
+	}
+	yy, mm, dd := d.YMD()
+	var b strings.Builder
+	for _, t := range toks {
+		switch t.kind {
+		case tokLiteral:
+			b.WriteString(t.lit)
+		case tokYear:
+			fmt.Fprintf(&b, "%04d", yy)
+		case tokYear2:
+			fmt.Fprintf(&b, "%02d", yy%100)
+		case tokMonth:
+			b.WriteString(strconv.Itoa(mm))
+		case tokMonth2:
+			fmt.Fprintf(&b, "%02d", mm)
+		case tokMonthName:
+			b.WriteString(l.Months[mm-1])
+		case tokMonthShort:
+			b.WriteString(l.ShortMonths[mm-1])
+		case tokDay:
+			b.WriteString(strconv.Itoa(dd))
+		case tokDay2:
+			fmt.Fprintf(&b, "%02d", dd)
+		case tokDaySpace:
+			fmt.Fprintf(&b, "%2d", dd)
+		case tokYearDay:
+			fmt.Fprintf(&b, "%03d", daysFromCivil(yy, mm, dd)-daysFromCivil(yy, 1, 1)+1)
+		case tokWeekday:
+			b.WriteString(l.Weekdays[d.Weekday()])
+		case tokWeekdayShort:
+			b.WriteString(l.ShortWeekdays[d.Weekday()])
+		case tokWeekdayNum:
+			b.WriteString(strconv.Itoa((int(d.Weekday())+6)%7 + 1))
+		case tokWeekdayNum0:
+			b.WriteString(strconv.Itoa(int(d.Weekday())))
+		}
+	}
+	return b.String(), nil
+}
+
+// ParseDate parses s with a strftime ("%d/%m/%Y") or Go ("02/01/2006") layout.
+// Month and weekday names are English.
+func ParseDate(s, layout string) (NullDate, error) {
+	l, _ := DateLocaleFor("en")
+	return parseDateLayout(s, layout, l)
+}
+
+// ParseDateLocale parses a date written in locale: numeric dates in the
+// locale's field order ("09/03/2018" is March 9 in pt-BR and September 3 in
+// en-US), ISO dates ("2018-03-09") and the locale's textual layouts.
+func ParseDateLocale(s, locale string) (NullDate, error) {
+	l, ok := DateLocaleFor(locale)
+	if !ok {
+		return NullDate{}, fmt.Errorf("unknown date locale '%s'", locale)
+	}
+	s = strings.TrimSpace(s)
+	if d, err := parseDateLayout(s, "2006-01-02", l); err == nil {
+		return d, nil
+	}
+	if d, ok, err := parseNumericDate(s, l); ok {
+		return d, err
+	}
+	for _, layout := range l.Layouts {
+		if d, err := parseDateLayout(s, layout, l); err == nil {
+			return d, nil
+		}
+	}
+	return NullDate{}, fmt.Errorf("invalid date '%s' for locale %s", s, l.Name)
+}
+
+// parseNumericDate parses "d/m/y" style dates. ok is false if s isn't a
+// numeric date.
+func parseNumericDate(s string, l *DateLocale) (d NullDate, ok bool, err error) {
+	parts := strings.FieldsFunc(s, func(r rune) bool {
+		return strings.ContainsRune(l.Separators, r)
+	})
+	if len(parts) != 3 {
+		return NullDate{}, false, nil
+	}
+	var n [3]int
+	for i, p := range parts {
+		v, err := strconv.Atoi(p)
+		if err != nil || v < 0 || len(p) > 4 {
+			return NullDate{}, false, nil
+		}
+		n[i] = v
+	}
+	var yy, mm, dd, yi int
+	switch l.Order {
+	case DMY:
+		dd, mm, yy, yi = n[0], n[1], n[2], 2
+	case MDY:
+		mm, dd, yy, yi = n[0], n[1], n[2], 2
+	default:
+		yy, mm, dd, yi = n[0], n[1], n[2], 0
+	}
+	if len(parts[yi]) <= 2 {
+		yy = expandYear(yy, l.pivot())
+	}
+	if !isValidDate(yy, mm, dd) {
+		return NullDate{}, true, fmt.Errorf("invalid date '%s'", s)
+	}
+	return packDate(yy, mm, dd), true, nil
+}
+
+func expandYear(yy, pivot int) int {
+	if yy < pivot {
+		return 2000 + yy
+	}
+	return 1900 + yy
+}
+
+func parseDateLayout(s, layout string, l *DateLocale) (NullDate, error) {
+	toks, err := tokenizeDateLayout(layout)
+	if err != nil {
+		return NullDate{}, err
+	}
+	orig := s
+	bad := func() (NullDate, error) {
+		return NullDate{}, fmt.Errorf("invalid date '%s' for layout '%s'", orig, layout)
+	}
+	yy, mm, dd, yday := -1, -1, -1, -1
+	for _, t := range toks {
+		var n int
+		var ok bool
+		switch t.kind {
+		case tokLiteral:
+			if !strings.HasPrefix(s, t.lit) {
+				return bad()
+			}
+			s = s[len(t.lit):]
+		case tokYear:
+			if yy, s, ok = parseDigits(s, 4, 4); !ok {
+				return bad()
+			}
+		case tokYear2:
+			if n, s, ok = parseDigits(s, 2, 2); !ok {
+				return bad()
+			}
+			yy = expandYear(n, l.pivot())
+		case tokMonth, tokMonth2:
+			if mm, s, ok = parseDigits(s, 1, 2); !ok {
+				return bad()
+			}
+		case tokDay, tokDay2, tokDaySpace:
+			if t.kind == tokDaySpace {
+				s = strings.TrimPrefix(s, " ")
+			}
+			if dd, s, ok = parseDigits(s, 1, 2); !ok {
+				return bad()
+			}
+		case tokYearDay:
+			if yday, s, ok = parseDigits(s, 1, 3); !ok {
+				return bad()
+			}
+		case tokWeekdayNum, tokWeekdayNum0:
+			if _, s, ok = parseDigits(s, 1, 1); !ok {
+				return bad()
+			}
+		case tokMonthName, tokMonthShort:
+			names := l.Months[:]
+			if t.kind == tokMonthShort {
+				names = l.ShortMonths[:]
+			}
+			if n, s, ok = parseName(s, names); !ok {
+				return bad()
+			}
+			mm = n + 1
+		case tokWeekday, tokWeekdayShort:
+			names := l.Weekdays[:]
+			if t.kind == tokWeekdayShort {
+				names = l.ShortWeekdays[:]
+			}
+			if _, s, ok = parseName(s, names); !ok {
+				return bad()
+			}
+		}
+	}
+	if s != "" {
+		return bad()
+	}
+	if yday > 0 && yy > 0 && mm < 0 && dd < 0 {
+		d := packDate(yy, 1, 1).AddDays(yday - 1)
+		if d.Year() != yy {
+			return bad()
+		}
+		return d, nil
+	}
+	if !isValidDate(yy, mm, dd) {
+		return bad()
+	}
+	return packDate(yy, mm, dd), nil
+}
+
+// parseDigits reads between min and max digits from the start of s
+func parseDigits(s string, min, max int) (n int, rest string, ok bool) {
+	i := 0
+	for i < len(s) && i < max && s[i] >= '0' && s[i] <= '9' {
+		n = n*10 + int(s[i]-'0')
+		i++
+	}
+	if i < min {
+		return 0, s, false
+	}
+	return n, s[i:], true
+}
+
+// parseName matches the longest of names (case insensitive) at the start
+// of s
+func parseName(s string, names []string) (idx int, rest string, ok bool) {
+	best := -1
+	for i, name := range names {
+		if len(name) <= len(s) && strings.EqualFold(s[:len(name)], name) {
+			if best < 0 || len(name) > len(names[best]) {
+				best = i
+			}
+		}
+	}
+	if best < 0 {
+		return 0, s, false
+	}
+	return best, s[len(names[best]):], true
+}
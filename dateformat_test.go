@@ -0,0 +1,74 @@
+package sqltypes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDateLocale(t *testing.T) {
+	d, err := ParseDateLocale("09/03/2018", "pt-BR")
+	assert.NoError(t, err)
+	assert.Equal(t, Date(2018, time.March, 9), d)
+	d, err = ParseDateLocale("03/09/2018", "en-US")
+	assert.NoError(t, err)
+	assert.Equal(t, Date(2018, time.March, 9), d)
+	d, err = ParseDateLocale("9.3.18", "pt_BR")
+	assert.NoError(t, err)
+	assert.Equal(t, Date(2018, time.March, 9), d)
+	d, err = ParseDateLocale("9/3/85", "pt")
+	assert.NoError(t, err)
+	assert.Equal(t, Date(1985, time.March, 9), d)
+	d, err = ParseDateLocale("2018-03-09", "en-US")
+	assert.NoError(t, err)
+	assert.Equal(t, Date(2018, time.March, 9), d)
+	d, err = ParseDateLocale("9 de março de 2018", "pt-BR")
+	assert.NoError(t, err)
+	assert.Equal(t, Date(2018, time.March, 9), d)
+	d, err = ParseDateLocale("March 9, 2018", "en")
+	assert.NoError(t, err)
+	assert.Equal(t, Date(2018, time.March, 9), d)
+	_, err = ParseDateLocale("31/02/2018", "pt-BR")
+	assert.Error(t, err)
+	_, err = ParseDateLocale("31/12/2018", "en-US")
+	assert.Error(t, err)
+	_, err = ParseDateLocale("09/03/2018", "xx")
+	assert.Error(t, err)
+}
+
+func TestParseDate(t *testing.T) {
+	d, err := ParseDate("09/03/2018", "%d/%m/%Y")
+	assert.NoError(t, err)
+	assert.Equal(t, Date(2018, time.March, 9), d)
+	d, err = ParseDate("Fri, 09 Mar 2018", "Mon, 02 Jan 2006")
+	assert.NoError(t, err)
+	assert.Equal(t, Date(2018, time.March, 9), d)
+	d, err = ParseDate("2018.068", "%Y.%j")
+	assert.NoError(t, err)
+	assert.Equal(t, Date(2018, time.March, 9), d)
+	_, err = ParseDate("09/03/2018 extra", "%d/%m/%Y")
+	assert.Error(t, err)
+	_, err = ParseDate("09/03/2018", "%d/%m/%Q")
+	assert.Error(t, err)
+}
+
+func TestNullDateFormat(t *testing.T) {
+	d := Date(2018, time.March, 9)
+	assert.Equal(t, "09/03/2018", d.Format("%d/%m/%Y"))
+	assert.Equal(t, "Friday, March 9, 2018", d.Format("Monday, January 2, 2006"))
+	assert.Equal(t, "2018-03-09 068 5", d.Format("%F %j %u"))
+	s, err := d.FormatLocale("%A, %e de %B de %Y", "pt-BR")
+	assert.NoError(t, err)
+	assert.Equal(t, "sexta-feira,  9 de março de 2018", s)
+	s, err = d.FormatLocale("Mon 2 Jan 06", "pt")
+	assert.NoError(t, err)
+	assert.Equal(t, "sex 9 mar 18", s)
+	assert.Equal(t, "", NullDate{}.Format("%Y"))
+	// round trip
+	s, err = d.FormatLocale("2 Jan 2006", "pt")
+	assert.NoError(t, err)
+	d2, err := ParseDateLocale(s, "pt")
+	assert.NoError(t, err)
+	assert.Equal(t, d, d2)
+}
@@ -0,0 +1,178 @@
+package sqltypes
+
+import (
+	"time"
+)
+
+// Calendar arithmetic on civil dates. None of these go through time.Time, so
+// there are no timezone or DST shifts. Operations on a NULL date return NULL.
+
+func isLeapYear(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
+
+// daysFromCivil returns the number of days since 1970-01-01
+func daysFromCivil(year, month, day int) int {
+	if month <= 2 {
+		year--
+	}
+	era := year / 400
+	yoe := year - era*400
+	doy := (153*((month+9)%12)+2)/5 + day - 1
+	doe := yoe*365 + yoe/4 - yoe/100 + doy
+	return era*146097 + doe - 719468
+}
+
+// civilFromDays is the inverse of daysFromCivil
+func civilFromDays(days int) (year, month, day int) {
+	days += 719468
+	era := days / 146097
+	doe := days - era*146097
+	yoe := (doe - doe/1460 + doe/36524 - doe/146096) / 365
+	doy := doe - (365*yoe + yoe/4 - yoe/100)
+	mp := (5*doy + 2) / 153
+	day = doy - (153*mp+2)/5 + 1
+	month = mp + 3
+	if month > 12 {
+		month -= 12
+	}
+	year = yoe + era*400
+	if month <= 2 {
+		year++
+	}
+	return
+}
+
+// dateFromDays returns the NullDate of days since 1970-01-01 (NULL if out
+// of range)
+func dateFromDays(days int) NullDate {
+	yy, mm, dd := civilFromDays(days)
+	if yy < 1 || yy > 9999 {
+		return NullDate{}
+	}
+	return packDate(yy, mm, dd)
+}
+
+func (d NullDate) days() int {
+	yy, mm, dd := d.YMD()
+	return daysFromCivil(yy, mm, dd)
+}
+
+// AddDays returns d+n days
+func (d NullDate) AddDays(n int) NullDate {
+	if d.IsZero() {
+		return d
+	}
+	return dateFromDays(d.days() + n)
+}
+
+// AddMonths returns d+n months. The day is clamped to the end of the
+// resulting month (2019-01-31 + 1 month = 2019-02-28).
+func (d NullDate) AddMonths(n int) NullDate {
+	if d.IsZero() {
+		return d
+	}
+	yy, mm, dd := d.YMD()
+	m := yy*12 + (mm - 1) + n
+	yy, mm = m/12, m%12+1
+	if m < 0 || yy < 1 || yy > 9999 {
+		return NullDate{}
+	}
+	if last := daysIn(yy, time.Month(mm)); dd > last {
+		dd = last
+	}
+	return packDate(yy, mm, dd)
+}
+
+// AddYears returns d+n years. February 29 becomes February 28 on non-leap
+// years.
+func (d NullDate) AddYears(n int) NullDate {
+	return d.AddMonths(n * 12)
+}
+
+// DaysBetween returns the number of days from d to u (negative if u is
+// before d). ok is false if either date is NULL.
+func (d NullDate) DaysBetween(u NullDate) (n int, ok bool) {
+	if d.IsZero() || u.IsZero() {
+		return 0, false
+	}
+	return u.days() - d.days(), true
+}
+
+// Compare returns -1, 0 or +1 if d is before, equal to or after u. NULL
+// sorts before any date.
+func (d NullDate) Compare(u NullDate) int {
+	switch {
+	case d.v < u.v:
+		return -1
+	case d.v > u.v:
+		return 1
+	}
+	return 0
+}
+
+// Before reports whether d is before u
+func (d NullDate) Before(u NullDate) bool {
+	return d.v < u.v
+}
+
+// After reports whether d is after u
+func (d NullDate) After(u NullDate) bool {
+	return d.v > u.v
+}
+
+// Equal reports whether d and u are the same date
+func (d NullDate) Equal(u NullDate) bool {
+	return d.v == u.v
+}
+
+// Weekday returns the day of the week (Sunday if NULL)
+func (d NullDate) Weekday() time.Weekday {
+	if d.IsZero() {
+		return time.Sunday
+	}
+	// 1970-01-01 was a Thursday
+	return time.Weekday(((d.days()+4)%7 + 7) % 7)
+}
+
+// ISOWeek returns the ISO 8601 year and week number
+func (d NullDate) ISOWeek() (year, week int) {
+	if d.IsZero() {
+		return 0, 0
+	}
+	// the week belongs to the year of its Thursday
+	days := d.days()
+	thursday := days - (int(d.Weekday())+6)%7 + 3
+	year, _, _ = civilFromDays(thursday)
+	week = (thursday-daysFromCivil(year, 1, 1))/7 + 1
+	return
+}
+
+// Quarter returns the quarter of the year (1-4, 0 if NULL)
+func (d NullDate) Quarter() int {
+	if d.IsZero() {
+		return 0
+	}
+	return (int(d.Month())-1)/3 + 1
+}
+
+// StartOfMonth returns the first day of the month
+func (d NullDate) StartOfMonth() NullDate {
+	if d.IsZero() {
+		return d
+	}
+	return packDate(d.Year(), int(d.Month()), 1)
+}
+
+// EndOfMonth returns the last day of the month
+func (d NullDate) EndOfMonth() NullDate {
+	if d.IsZero() {
+		return d
+	}
+	return packDate(d.Year(), int(d.Month()), daysIn(d.Year(), d.Month()))
+}
+
+// IsLeapYear reports whether d is in a leap year
+func (d NullDate) IsLeapYear() bool {
+	return !d.IsZero() && isLeapYear(d.Year())
+}
@@ -0,0 +1,72 @@
+package sqltypes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNullDateCivilDays(t *testing.T) {
+	// compare against time.Time for every day of a few centuries
+	tt := time.Date(1890, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 200*366; i++ {
+		d := DateFromTime(tt)
+		assert.Equal(t, int(tt.Unix()/86400), d.days())
+		assert.Equal(t, d, dateFromDays(d.days()))
+		assert.Equal(t, tt.Weekday(), d.Weekday())
+		yy, ww := tt.ISOWeek()
+		y2, w2 := d.ISOWeek()
+		if yy != y2 || ww != w2 {
+			t.Fatalf("ISOWeek(%v) = %d-%d, want %d-%d", d, y2, w2, yy, ww)
+		}
+		tt = tt.AddDate(0, 0, 1)
+	}
+}
+
+func TestNullDateAdd(t *testing.T) {
+	d := Date(2019, time.January, 31)
+	assert.Equal(t, Date(2019, time.February, 1), d.AddDays(1))
+	assert.Equal(t, Date(2018, time.December, 31), d.AddDays(-31))
+	assert.Equal(t, Date(2019, time.February, 28), d.AddMonths(1))
+	assert.Equal(t, Date(2020, time.February, 29), d.AddMonths(13))
+	assert.Equal(t, Date(2018, time.November, 30), d.AddMonths(-2))
+	assert.Equal(t, Date(2021, time.February, 28), Date(2020, time.February, 29).AddYears(1))
+	assert.True(t, NullDate{}.AddDays(1).IsZero())
+	assert.True(t, Date(9999, time.December, 31).AddDays(1).IsZero())
+}
+
+func TestNullDateCompare(t *testing.T) {
+	a := Date(2019, time.July, 22)
+	b := Date(2020, time.January, 1)
+	n, ok := a.DaysBetween(b)
+	assert.True(t, ok)
+	assert.Equal(t, 163, n)
+	n, ok = b.DaysBetween(a)
+	assert.True(t, ok)
+	assert.Equal(t, -163, n)
+	_, ok = NullDate{}.DaysBetween(a)
+	assert.False(t, ok)
+	_, ok = a.DaysBetween(NullDate{})
+	assert.False(t, ok)
+	assert.True(t, a.Before(b))
+	assert.True(t, b.After(a))
+	assert.True(t, a.Equal(DateFromString("2019-07-22")))
+	assert.Equal(t, -1, a.Compare(b))
+	assert.Equal(t, 0, a.Compare(a))
+	assert.Equal(t, 1, a.Compare(NullDate{}))
+}
+
+func TestNullDateCalendar(t *testing.T) {
+	d := Date(2020, time.February, 12)
+	assert.Equal(t, 1, d.Quarter())
+	assert.Equal(t, 4, Date(2020, time.October, 1).Quarter())
+	assert.Equal(t, Date(2020, time.February, 1), d.StartOfMonth())
+	assert.Equal(t, Date(2020, time.February, 29), d.EndOfMonth())
+	assert.True(t, d.IsLeapYear())
+	assert.False(t, Date(1900, time.March, 1).IsLeapYear())
+	assert.True(t, Date(2000, time.March, 1).IsLeapYear())
+	yy, ww := Date(2021, time.January, 3).ISOWeek()
+	assert.Equal(t, 2020, yy)
+	assert.Equal(t, 53, ww)
+}
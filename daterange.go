@@ -0,0 +1,603 @@
+package sqltypes
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// NullDateRange is a range of dates (Postgres daterange). A zero Lower or
+// Upper is unbounded. Like Postgres, ranges are kept in the canonical [)
+// form. Valid = false is NULL.
+type NullDateRange struct {
+	Lower    NullDate
+	Upper    NullDate
+	LowerInc bool
+	UpperInc bool
+	Empty    bool
+	Valid    bool
+}
+
+// DateRange returns a canonical NullDateRange; bounds is "[)", "[]", "()" or
+// "(]" like the Postgres daterange constructor.
+func DateRange(lower, upper NullDate, bounds string) (NullDateRange, error) {
+	if len(bounds) != 2 || !strings.Contains("[(", bounds[:1]) || !strings.Contains("])", bounds[1:]) {
+		return NullDateRange{}, fmt.Errorf("invalid range bounds '%s'", bounds)
+	}
+	r := NullDateRange{
+		Lower:    lower,
+		Upper:    upper,
+		LowerInc: bounds[0] == '[',
+		UpperInc: bounds[1] == ']',
+		Valid:    true,
+	}
+	if !lower.IsZero() && !upper.IsZero() && lower.After(upper) {
+		return NullDateRange{}, fmt.Errorf("range lower bound %v must be less than or equal to upper bound %v", lower, upper)
+	}
+	return r.canonical()
+}
+
+func cmpDates(a, b interface{}) int {
+	return a.(NullDate).Compare(b.(NullDate))
+}
+
+// canonical converts r to [) form. There is no date after 9999-12-31, so an
+// exclusive lower bound there is empty and an inclusive upper bound is an
+// error.
+func (r NullDateRange) canonical() (NullDateRange, error) {
+	if !r.Valid || r.Empty {
+		return r, nil
+	}
+	if !r.Lower.IsZero() && !r.LowerInc {
+		next := r.Lower.AddDays(1)
+		if next.IsZero() {
+			return NullDateRange{Empty: true, Valid: true}, nil
+		}
+		r.Lower, r.LowerInc = next, true
+	}
+	if !r.Upper.IsZero() && r.UpperInc {
+		next := r.Upper.AddDays(1)
+		if next.IsZero() {
+			return NullDateRange{}, fmt.Errorf("range bound %v out of range", r.Upper)
+		}
+		r.Upper, r.UpperInc = next, false
+	}
+	if r.Lower.IsZero() {
+		r.LowerInc = false
+	}
+	return dateRangeFromSpan(r.span().normalize()), nil
+}
+
+// mustCanonical is canonical for ranges built by the set operations, whose
+// bounds come from already canonical ranges
+func (r NullDateRange) mustCanonical() NullDateRange {
+	c, err := r.canonical()
+	if err != nil {
+		return r
+	}
+	return c
+}
+
+func (r NullDateRange) span() rangeSpan {
+	return rangeSpan{
+		lower: rangeEnd{val: r.Lower, inf: r.Lower.IsZero(), inc: r.LowerInc},
+		upper: rangeEnd{val: r.Upper, inf: r.Upper.IsZero(), inc: r.UpperInc},
+		empty: r.Empty || !r.Valid,
+		cmp:   cmpDates,
+	}
+}
+
+func dateRangeFromSpan(s rangeSpan) NullDateRange {
+	if s.empty {
+		return NullDateRange{Empty: true, Valid: true}
+	}
+	r := NullDateRange{LowerInc: s.lower.inc, UpperInc: s.upper.inc, Valid: true}
+	if !s.lower.inf {
+		r.Lower = s.lower.val.(NullDate)
+	}
+	if !s.upper.inf {
+		r.Upper = s.upper.val.(NullDate)
+	}
+	return r
+}
+
+// Contains reports whether d is in r
+func (r NullDateRange) Contains(d NullDate) bool {
+	return !d.IsZero() && r.mustCanonical().span().contains(d)
+}
+
+// ContainsRange reports whether o is entirely in r
+func (r NullDateRange) ContainsRange(o NullDateRange) bool {
+	return r.Valid && o.Valid && r.mustCanonical().span().containsRange(o.mustCanonical().span())
+}
+
+// Overlaps reports whether r and o have dates in common
+func (r NullDateRange) Overlaps(o NullDateRange) bool {
+	return r.mustCanonical().span().overlaps(o.mustCanonical().span())
+}
+
+// Adjacent reports whether r and o touch without overlapping
+func (r NullDateRange) Adjacent(o NullDateRange) bool {
+	return r.mustCanonical().span().adjacent(o.mustCanonical().span())
+}
+
+// Intersect returns the dates in both r and o
+func (r NullDateRange) Intersect(o NullDateRange) NullDateRange {
+	if !r.Valid || !o.Valid {
+		return NullDateRange{}
+	}
+	return dateRangeFromSpan(r.mustCanonical().span().intersect(o.mustCanonical().span()))
+}
+
+// Union returns the dates in r or o. ok is false if r and o neither overlap
+// nor are adjacent, as the result wouldn't be contiguous.
+func (r NullDateRange) Union(o NullDateRange) (NullDateRange, bool) {
+	if !r.Valid || !o.Valid {
+		return NullDateRange{}, false
+	}
+	s, ok := r.mustCanonical().span().union(o.mustCanonical().span())
+	if !ok {
+		return NullDateRange{}, false
+	}
+	return dateRangeFromSpan(s), true
+}
+
+// Difference returns the dates in r but not in o. ok is false if o is
+// strictly inside r, as the result wouldn't be contiguous.
+func (r NullDateRange) Difference(o NullDateRange) (NullDateRange, bool) {
+	if !r.Valid || !o.Valid {
+		return NullDateRange{}, false
+	}
+	s, ok := r.mustCanonical().span().difference(o.mustCanonical().span())
+	if !ok {
+		return NullDateRange{}, false
+	}
+	return dateRangeFromSpan(s).mustCanonical(), true
+}
+
+// Days returns the number of dates in r. ok is false if r is unbounded.
+func (r NullDateRange) Days() (n int, ok bool) {
+	if !r.Valid || r.Empty {
+		return 0, true
+	}
+	if r.Lower.IsZero() || r.Upper.IsZero() {
+		return 0, false
+	}
+	c, err := r.canonical()
+	if err != nil {
+		return 0, false
+	}
+	if c.Empty {
+		return 0, true
+	}
+	return c.Lower.DaysBetween(c.Upper)
+}
+
+// Duration returns the length of r in 24 hour days. ok is false if r is
+// unbounded.
+func (r NullDateRange) Duration() (time.Duration, bool) {
+	n, ok := r.Days()
+	return time.Duration(n) * oneDay, ok
+}
+
+// String returns r in the Postgres range format or "" if NULL
+func (r NullDateRange) String() string {
+	if !r.Valid {
+		return ""
+	}
+	return formatRangeText(rangeText{
+		lower:    r.Lower.String(),
+		upper:    r.Upper.String(),
+		lowerInf: r.Lower.IsZero(),
+		upperInf: r.Upper.IsZero(),
+		lowerInc: r.LowerInc,
+		upperInc: r.UpperInc,
+		empty:    r.Empty,
+	})
+}
+
+// Scan implements the Scanner interface.
+func (r *NullDateRange) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*r = NullDateRange{}
+		return nil
+	case []byte:
+		return r.parse(string(v))
+	case string:
+		return r.parse(v)
+	}
+	return fmt.Errorf("unsupported Scan, storing driver.Value type %T into type %T", value, r)
+}
+
+func (r *NullDateRange) parse(s string) error {
+	rt, err := parseRangeText(s)
+	if err != nil {
+		return err
+	}
+	if rt.empty {
+		*r = NullDateRange{Empty: true, Valid: true}
+		return nil
+	}
+	var lower, upper NullDate
+	if !rt.lowerInf && !isInfinity(rt.lower) {
+		if err := lower.strscan(rt.lower); err != nil {
+			return err
+		}
+	}
+	if !rt.upperInf && !isInfinity(rt.upper) {
+		if err := upper.strscan(rt.upper); err != nil {
+			return err
+		}
+	}
+	nr, err := DateRange(lower, upper, rangeBounds(rt.lowerInc, rt.upperInc))
+	if err != nil {
+		return err
+	}
+	*r = nr
+	return nil
+}
+
+func isInfinity(s string) bool {
+	return strings.EqualFold(s, "infinity") || strings.EqualFold(s, "-infinity")
+}
+
+func rangeBounds(lowerInc, upperInc bool) string {
+	b := []byte("()")
+	if lowerInc {
+		b[0] = '['
+	}
+	if upperInc {
+		b[1] = ']'
+	}
+	return string(b)
+}
+
+// Value implements the driver Valuer interface.
+func (r NullDateRange) Value() (driver.Value, error) {
+	if !r.Valid {
+		return nil, nil
+	}
+	return r.String(), nil
+}
+
+// rangeJSON is the JSON form of the range types:
+// {"lower":...,"upper":...,"bounds":"[)"} or {"empty":true}
+type rangeJSON struct {
+	Lower  json.RawMessage `json:"lower,omitempty"`
+	Upper  json.RawMessage `json:"upper,omitempty"`
+	Bounds string          `json:"bounds,omitempty"`
+	Empty  bool            `json:"empty,omitempty"`
+}
+
+// unmarshalRangeJSON decodes the object form (or a Postgres range string)
+// of a range. ok is false for null.
+func unmarshalRangeJSON(v []byte, parse func(string) error) (rj rangeJSON, ok bool, err error) {
+	if len(v) == 0 || string(v) == "null" {
+		return rj, false, nil
+	}
+	if v[0] == '"' {
+		var s string
+		if err := json.Unmarshal(v, &s); err != nil {
+			return rj, false, err
+		}
+		return rj, false, parse(s)
+	}
+	if err := json.Unmarshal(v, &rj); err != nil {
+		return rj, false, err
+	}
+	if rj.Bounds == "" {
+		rj.Bounds = "[)"
+	}
+	return rj, true, nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (r NullDateRange) MarshalJSON() ([]byte, error) {
+	if !r.Valid {
+		return []byte("null"), nil
+	}
+	if r.Empty {
+		return json.Marshal(rangeJSON{Empty: true})
+	}
+	rj := rangeJSON{Lower: json.RawMessage("null"), Upper: json.RawMessage("null"), Bounds: rangeBounds(r.LowerInc, r.UpperInc)}
+	if !r.Lower.IsZero() {
+		rj.Lower, _ = r.Lower.MarshalJSON()
+	}
+	if !r.Upper.IsZero() {
+		rj.Upper, _ = r.Upper.MarshalJSON()
+	}
+	return json.Marshal(rj)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (r *NullDateRange) UnmarshalJSON(v []byte) error {
+	*r = NullDateRange{}
+	rj, ok, err := unmarshalRangeJSON(v, r.parse)
+	if err != nil || !ok {
+		return err
+	}
+	if rj.Empty {
+		*r = NullDateRange{Empty: true, Valid: true}
+		return nil
+	}
+	var lower, upper NullDate
+	if len(rj.Lower) > 0 {
+		if err := lower.UnmarshalJSON(rj.Lower); err != nil {
+			return err
+		}
+	}
+	if len(rj.Upper) > 0 {
+		if err := upper.UnmarshalJSON(rj.Upper); err != nil {
+			return err
+		}
+	}
+	nr, err := DateRange(lower, upper, rj.Bounds)
+	if err != nil {
+		return err
+	}
+	*r = nr
+	return nil
+}
+
+// NullTimeRange is a range of timestamps (Postgres tsrange/tstzrange). A zero
+// Lower or Upper is unbounded. Valid = false is NULL.
+type NullTimeRange struct {
+	Lower    NullTime
+	Upper    NullTime
+	LowerInc bool
+	UpperInc bool
+	Empty    bool
+	Valid    bool
+}
+
+// TimeRange returns a NullTimeRange; bounds is "[)", "[]", "()" or "(]".
+func TimeRange(lower, upper time.Time, bounds string) (NullTimeRange, error) {
+	if len(bounds) != 2 || !strings.Contains("[(", bounds[:1]) || !strings.Contains("])", bounds[1:]) {
+		return NullTimeRange{}, fmt.Errorf("invalid range bounds '%s'", bounds)
+	}
+	if !lower.IsZero() && !upper.IsZero() && lower.After(upper) {
+		return NullTimeRange{}, fmt.Errorf("range lower bound %v must be less than or equal to upper bound %v", lower, upper)
+	}
+	r := NullTimeRange{
+		Lower:    NullTime(lower),
+		Upper:    NullTime(upper),
+		LowerInc: bounds[0] == '[' && !lower.IsZero(),
+		UpperInc: bounds[1] == ']' && !upper.IsZero(),
+		Valid:    true,
+	}
+	return timeRangeFromSpan(r.span().normalize()), nil
+}
+
+func cmpTimes(a, b interface{}) int {
+	ta, tb := a.(NullTime).T(), b.(NullTime).T()
+	switch {
+	case ta.Before(tb):
+		return -1
+	case ta.After(tb):
+		return 1
+	}
+	return 0
+}
+
+func (r NullTimeRange) span() rangeSpan {
+	return rangeSpan{
+		lower: rangeEnd{val: r.Lower, inf: r.Lower.T().IsZero(), inc: r.LowerInc},
+		upper: rangeEnd{val: r.Upper, inf: r.Upper.T().IsZero(), inc: r.UpperInc},
+		empty: r.Empty || !r.Valid,
+		cmp:   cmpTimes,
+	}
+}
+
+func timeRangeFromSpan(s rangeSpan) NullTimeRange {
+	if s.empty {
+		return NullTimeRange{Empty: true, Valid: true}
+	}
+	r := NullTimeRange{LowerInc: s.lower.inc, UpperInc: s.upper.inc, Valid: true}
+	if !s.lower.inf {
+		r.Lower = s.lower.val.(NullTime)
+	}
+	if !s.upper.inf {
+		r.Upper = s.upper.val.(NullTime)
+	}
+	return r
+}
+
+// Contains reports whether t is in r
+func (r NullTimeRange) Contains(t time.Time) bool {
+	return !t.IsZero() && r.span().contains(NullTime(t))
+}
+
+// ContainsRange reports whether o is entirely in r
+func (r NullTimeRange) ContainsRange(o NullTimeRange) bool {
+	return r.Valid && o.Valid && r.span().containsRange(o.span())
+}
+
+// Overlaps reports whether r and o have instants in common
+func (r NullTimeRange) Overlaps(o NullTimeRange) bool {
+	return r.span().overlaps(o.span())
+}
+
+// Adjacent reports whether r and o touch without overlapping
+func (r NullTimeRange) Adjacent(o NullTimeRange) bool {
+	return r.span().adjacent(o.span())
+}
+
+// Intersect returns the instants in both r and o
+func (r NullTimeRange) Intersect(o NullTimeRange) NullTimeRange {
+	if !r.Valid || !o.Valid {
+		return NullTimeRange{}
+	}
+	return timeRangeFromSpan(r.span().intersect(o.span()))
+}
+
+// Union returns the instants in r or o. ok is false if r and o neither
+// overlap nor are adjacent.
+func (r NullTimeRange) Union(o NullTimeRange) (NullTimeRange, bool) {
+	if !r.Valid || !o.Valid {
+		return NullTimeRange{}, false
+	}
+	s, ok := r.span().union(o.span())
+	if !ok {
+		return NullTimeRange{}, false
+	}
+	return timeRangeFromSpan(s), true
+}
+
+// Difference returns the instants in r but not in o. ok is false if o is
+// strictly inside r.
+func (r NullTimeRange) Difference(o NullTimeRange) (NullTimeRange, bool) {
+	if !r.Valid || !o.Valid {
+		return NullTimeRange{}, false
+	}
+	s, ok := r.span().difference(o.span())
+	if !ok {
+		return NullTimeRange{}, false
+	}
+	return timeRangeFromSpan(s), true
+}
+
+// Duration returns the length of r. ok is false if r is unbounded.
+func (r NullTimeRange) Duration() (time.Duration, bool) {
+	if !r.Valid || r.Empty {
+		return 0, true
+	}
+	if r.Lower.T().IsZero() || r.Upper.T().IsZero() {
+		return 0, false
+	}
+	return r.Upper.T().Sub(r.Lower.T()), true
+}
+
+// String returns r in the Postgres range format or "" if NULL
+func (r NullTimeRange) String() string {
+	if !r.Valid {
+		return ""
+	}
+	return formatRangeText(rangeText{
+		lower:    TimeOffset(r.Lower.T()).String(),
+		upper:    TimeOffset(r.Upper.T()).String(),
+		lowerInf: r.Lower.T().IsZero(),
+		upperInf: r.Upper.T().IsZero(),
+		lowerInc: r.LowerInc,
+		upperInc: r.UpperInc,
+		empty:    r.Empty,
+	})
+}
+
+// Scan implements the Scanner interface.
+func (r *NullTimeRange) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*r = NullTimeRange{}
+		return nil
+	case []byte:
+		return r.parse(string(v))
+	case string:
+		return r.parse(v)
+	}
+	return fmt.Errorf("unsupported Scan, storing driver.Value type %T into type %T", value, r)
+}
+
+// parseRangeTime parses a tstzrange ("2006-01-02 15:04:05-07") or tsrange
+// ("2006-01-02 15:04:05", taken as UTC) bound
+func parseRangeTime(s string) (time.Time, error) {
+	if isInfinity(s) {
+		return time.Time{}, nil
+	}
+	var to NullTimeOffset
+	if err := to.parse(s); err == nil {
+		return to.T(), nil
+	}
+	return time.Parse("2006-01-02 15:04:05", strings.Replace(s, "T", " ", 1))
+}
+
+func (r *NullTimeRange) parse(s string) error {
+	rt, err := parseRangeText(s)
+	if err != nil {
+		return err
+	}
+	if rt.empty {
+		*r = NullTimeRange{Empty: true, Valid: true}
+		return nil
+	}
+	var lower, upper time.Time
+	if !rt.lowerInf {
+		if lower, err = parseRangeTime(rt.lower); err != nil {
+			return err
+		}
+	}
+	if !rt.upperInf {
+		if upper, err = parseRangeTime(rt.upper); err != nil {
+			return err
+		}
+	}
+	nr, err := TimeRange(lower, upper, rangeBounds(rt.lowerInc, rt.upperInc))
+	if err != nil {
+		return err
+	}
+	*r = nr
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (r NullTimeRange) Value() (driver.Value, error) {
+	if !r.Valid {
+		return nil, nil
+	}
+	return r.String(), nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (r NullTimeRange) MarshalJSON() ([]byte, error) {
+	if !r.Valid {
+		return []byte("null"), nil
+	}
+	if r.Empty {
+		return json.Marshal(rangeJSON{Empty: true})
+	}
+	rj := rangeJSON{Lower: json.RawMessage("null"), Upper: json.RawMessage("null"), Bounds: rangeBounds(r.LowerInc, r.UpperInc)}
+	var err error
+	if !r.Lower.T().IsZero() {
+		if rj.Lower, err = r.Lower.MarshalJSON(); err != nil {
+			return nil, err
+		}
+	}
+	if !r.Upper.T().IsZero() {
+		if rj.Upper, err = r.Upper.MarshalJSON(); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(rj)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (r *NullTimeRange) UnmarshalJSON(v []byte) error {
+	*r = NullTimeRange{}
+	rj, ok, err := unmarshalRangeJSON(v, r.parse)
+	if err != nil || !ok {
+		return err
+	}
+	if rj.Empty {
+		*r = NullTimeRange{Empty: true, Valid: true}
+		return nil
+	}
+	var lower, upper NullTime
+	if len(rj.Lower) > 0 {
+		if err := lower.UnmarshalJSON(rj.Lower); err != nil {
+			return err
+		}
+	}
+	if len(rj.Upper) > 0 {
+		if err := upper.UnmarshalJSON(rj.Upper); err != nil {
+			return err
+		}
+	}
+	nr, err := TimeRange(lower.T(), upper.T(), rj.Bounds)
+	if err != nil {
+		return err
+	}
+	*r = nr
+	return nil
+}
@@ -0,0 +1,220 @@
+package sqltypes
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNullDateRangeScan(t *testing.T) {
+	var r NullDateRange
+	assert.NoError(t, r.Scan("[2020-01-01,2020-02-01)"))
+	assert.Equal(t, Date(2020, time.January, 1), r.Lower)
+	assert.Equal(t, Date(2020, time.February, 1), r.Upper)
+	assert.True(t, r.LowerInc)
+	assert.False(t, r.UpperInc)
+	// canonical form
+	assert.NoError(t, r.Scan([]byte(`("2019-12-31",2020-01-31]`)))
+	assert.Equal(t, "[2020-01-01,2020-02-01)", r.String())
+	assert.NoError(t, r.Scan("(,2020-02-01]"))
+	assert.Equal(t, "(,2020-02-02)", r.String())
+	assert.NoError(t, r.Scan("[2020-01-01,infinity)"))
+	assert.Equal(t, "[2020-01-01,)", r.String())
+	assert.NoError(t, r.Scan("(2020-01-01,2020-01-02)"))
+	assert.True(t, r.Empty)
+	assert.NoError(t, r.Scan("empty"))
+	assert.True(t, r.Empty)
+	v, err := r.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "empty", v)
+	assert.Error(t, r.Scan("[2020-02-01,2020-01-01)"))
+	assert.Error(t, r.Scan("2020-01-01,2020-02-01"))
+	assert.Error(t, r.Scan("[2020-01-01,2020-02-01) x"))
+	// there is no date after 9999-12-31
+	assert.NoError(t, r.Scan("(9999-12-31,)"))
+	assert.True(t, r.Empty)
+	assert.NoError(t, r.Scan("(9999-12-31,9999-12-31]"))
+	assert.True(t, r.Empty)
+	assert.Error(t, r.Scan("[2020-01-01,9999-12-31]"))
+	_, err = DateRange(Date(2020, time.January, 1), Date(9999, time.December, 31), "[]")
+	assert.Error(t, err)
+	assert.NoError(t, r.Scan("[2020-01-01,9999-12-31)"))
+	assert.Equal(t, "[2020-01-01,9999-12-31)", r.String())
+	assert.NoError(t, r.Scan(nil))
+	assert.False(t, r.Valid)
+}
+
+func TestNullDateRangeOps(t *testing.T) {
+	jan, _ := DateRange(Date(2020, time.January, 1), Date(2020, time.January, 31), "[]")
+	feb, _ := DateRange(Date(2020, time.February, 1), Date(2020, time.March, 1), "[)")
+	mid, _ := DateRange(Date(2020, time.January, 15), Date(2020, time.February, 15), "[)")
+	since, _ := DateRange(Date(2020, time.January, 20), NullDate{}, "[)")
+	assert.True(t, jan.Contains(Date(2020, time.January, 31)))
+	assert.False(t, jan.Contains(Date(2020, time.February, 1)))
+	assert.True(t, since.Contains(Date(2099, time.January, 1)))
+	assert.False(t, jan.Overlaps(feb))
+	assert.True(t, jan.Adjacent(feb))
+	assert.True(t, jan.Overlaps(mid))
+	assert.Equal(t, "[2020-01-15,2020-02-01)", jan.Intersect(mid).String())
+	assert.True(t, jan.Intersect(feb).Empty)
+	u, ok := jan.Union(feb)
+	assert.True(t, ok)
+	assert.Equal(t, "[2020-01-01,2020-03-01)", u.String())
+	_, ok = jan.Union(NullDateRange{Lower: Date(2020, time.March, 1), LowerInc: true, Valid: true})
+	assert.False(t, ok)
+	assert.True(t, u.ContainsRange(mid))
+	n, ok := jan.Days()
+	assert.True(t, ok)
+	assert.Equal(t, 31, n)
+	d, ok := feb.Duration()
+	assert.True(t, ok)
+	assert.Equal(t, 29*24*time.Hour, d)
+	_, ok = since.Days()
+	assert.False(t, ok)
+	// literals are canonicalized first
+	none := NullDateRange{Lower: Date(2020, time.January, 1), Upper: Date(2020, time.January, 2), Valid: true}
+	first2, _ := DateRange(Date(2020, time.January, 1), Date(2020, time.January, 3), "[)")
+	assert.False(t, none.Overlaps(first2))
+	assert.False(t, first2.Overlaps(none))
+	assert.False(t, none.Contains(Date(2020, time.January, 1)))
+	assert.True(t, first2.ContainsRange(none))
+	assert.True(t, first2.Intersect(none).Empty)
+	janLit := NullDateRange{Lower: Date(2020, time.January, 1), Upper: Date(2020, time.January, 31), LowerInc: true, UpperInc: true, Valid: true}
+	fromFeb := NullDateRange{Lower: Date(2020, time.February, 1), LowerInc: true, Valid: true}
+	assert.True(t, janLit.Adjacent(fromFeb))
+	assert.True(t, fromFeb.Adjacent(janLit))
+	assert.False(t, janLit.Overlaps(fromFeb))
+	assert.True(t, janLit.Contains(Date(2020, time.January, 31)))
+	u, ok = janLit.Union(fromFeb)
+	assert.True(t, ok)
+	assert.Equal(t, "[2020-01-01,)", u.String())
+	dec31 := NullDateRange{Lower: Date(2019, time.December, 31), Upper: Date(2020, time.January, 31), Valid: true}
+	assert.Equal(t, "[2020-01-01,2020-01-31)", dec31.Intersect(janLit).String())
+	assert.True(t, janLit.ContainsRange(dec31))
+	assert.False(t, dec31.ContainsRange(janLit))
+}
+
+func TestNullDateRangeDifference(t *testing.T) {
+	jan, _ := DateRange(Date(2020, time.January, 1), Date(2020, time.January, 31), "[]")
+	mid, _ := DateRange(Date(2020, time.January, 10), Date(2020, time.January, 20), "[)")
+	late, _ := DateRange(Date(2020, time.January, 20), NullDate{}, "[)")
+	d, ok := jan.Difference(late)
+	assert.True(t, ok)
+	assert.Equal(t, "[2020-01-01,2020-01-20)", d.String())
+	d, ok = late.Difference(jan)
+	assert.True(t, ok)
+	assert.Equal(t, "[2020-02-01,)", d.String())
+	// would be split in two
+	_, ok = jan.Difference(mid)
+	assert.False(t, ok)
+	// empty results
+	d, ok = mid.Difference(jan)
+	assert.True(t, ok)
+	assert.True(t, d.Empty)
+	d, ok = jan.Difference(jan)
+	assert.True(t, ok)
+	assert.True(t, d.Empty)
+	// no overlap and empty operands
+	feb, _ := DateRange(Date(2020, time.February, 1), Date(2020, time.March, 1), "[)")
+	d, ok = jan.Difference(feb)
+	assert.True(t, ok)
+	assert.Equal(t, jan, d)
+	d, ok = jan.Difference(NullDateRange{Empty: true, Valid: true})
+	assert.True(t, ok)
+	assert.Equal(t, jan, d)
+	// literals with exclusive and inclusive bounds
+	lit := NullDateRange{Lower: Date(2019, time.December, 31), Upper: Date(2020, time.January, 9), UpperInc: true, Valid: true}
+	d, ok = jan.Difference(lit)
+	assert.True(t, ok)
+	assert.Equal(t, "[2020-01-10,2020-02-01)", d.String())
+	_, ok = NullDateRange{}.Difference(jan)
+	assert.False(t, ok)
+}
+
+func TestNullTimeRangeDifference(t *testing.T) {
+	at := func(h int) time.Time { return time.Date(2020, 1, 1, h, 0, 0, 0, time.UTC) }
+	day, _ := TimeRange(at(0), at(24), "[)")
+	morning, _ := TimeRange(at(0), at(12), "[)")
+	lunch, _ := TimeRange(at(12), at(13), "[]")
+	d, ok := day.Difference(morning)
+	assert.True(t, ok)
+	assert.True(t, d.Lower.T().Equal(at(12)))
+	assert.True(t, d.LowerInc)
+	assert.True(t, d.Upper.T().Equal(at(24)))
+	_, ok = day.Difference(lunch)
+	assert.False(t, ok)
+	d, ok = morning.Difference(lunch)
+	assert.True(t, ok)
+	assert.Equal(t, morning, d)
+	afternoon, _ := TimeRange(at(12), at(24), "[)")
+	d, ok = afternoon.Difference(lunch)
+	assert.True(t, ok)
+	assert.True(t, d.Lower.T().Equal(at(13)))
+	assert.False(t, d.LowerInc)
+	d, ok = morning.Difference(day)
+	assert.True(t, ok)
+	assert.True(t, d.Empty)
+	since, _ := TimeRange(at(6), time.Time{}, "[)")
+	d, ok = since.Difference(day)
+	assert.True(t, ok)
+	assert.True(t, d.Lower.T().Equal(at(24)))
+	assert.True(t, d.LowerInc)
+	assert.True(t, d.Upper.T().IsZero())
+}
+
+func TestNullDateRangeJSON(t *testing.T) {
+	r, err := DateRange(Date(2020, time.January, 1), NullDate{}, "[)")
+	assert.NoError(t, err)
+	bb, err := json.Marshal(r)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"lower":"2020-01-01","upper":null,"bounds":"[)"}`, string(bb))
+	var r2 NullDateRange
+	assert.NoError(t, json.Unmarshal(bb, &r2))
+	assert.Equal(t, r, r2)
+	assert.NoError(t, json.Unmarshal([]byte(`"[2020-01-01,2020-01-05]"`), &r2))
+	assert.Equal(t, "[2020-01-01,2020-01-06)", r2.String())
+	assert.NoError(t, json.Unmarshal([]byte(`{"empty":true}`), &r2))
+	assert.True(t, r2.Empty)
+	bb, err = json.Marshal(r2)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"empty":true}`, string(bb))
+	assert.NoError(t, json.Unmarshal([]byte(`null`), &r2))
+	assert.False(t, r2.Valid)
+}
+
+func TestNullTimeRange(t *testing.T) {
+	var r NullTimeRange
+	assert.NoError(t, r.Scan(`["2020-01-01 00:00:00+00","2020-01-01 12:00:00+00")`))
+	d, ok := r.Duration()
+	assert.True(t, ok)
+	assert.Equal(t, 12*time.Hour, d)
+	assert.True(t, r.Contains(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, r.Contains(time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)))
+	assert.True(t, r.Contains(time.Date(2020, 1, 1, 8, 0, 0, 0, time.FixedZone("", 3600))))
+	assert.Equal(t, `["2020-01-01 00:00:00+00:00","2020-01-01 12:00:00+00:00")`, r.String())
+	//
+	r2, err := TimeRange(time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC), time.Time{}, "[)")
+	assert.NoError(t, err)
+	assert.True(t, r.Adjacent(r2))
+	assert.False(t, r.Overlaps(r2))
+	u, ok := r.Union(r2)
+	assert.True(t, ok)
+	assert.Equal(t, `["2020-01-01 00:00:00+00:00",)`, u.String())
+	_, ok = u.Duration()
+	assert.False(t, ok)
+	assert.True(t, u.Intersect(r).Lower.T().Equal(r.Lower.T()))
+	//
+	assert.NoError(t, r.Scan("[2020-01-01 00:00:00,2020-01-01 00:00:00]"))
+	assert.False(t, r.Empty)
+	assert.NoError(t, r.Scan("[2020-01-01 00:00:00,2020-01-01 00:00:00)"))
+	assert.True(t, r.Empty)
+	//
+	bb, err := json.Marshal(r2)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"lower":"2020-01-01T12:00:00Z","upper":null,"bounds":"[)"}`, string(bb))
+	var r3 NullTimeRange
+	assert.NoError(t, json.Unmarshal(bb, &r3))
+	assert.True(t, r3.Lower.T().Equal(r2.Lower.T()))
+}
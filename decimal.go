@@ -0,0 +1,79 @@
+package sqltypes
+
+import (
+	"database/sql/driver"
+
+	"github.com/shopspring/decimal"
+)
+
+// NullDecimalOpt is a decimal that tracks NULL explicitly. Unlike NullDecimal,
+// Valid = false is written as NULL, Scan(nil) resets it and it marshals to
+// JSON null.
+type NullDecimalOpt struct {
+	Decimal decimal.Decimal
+	Valid   bool
+}
+
+// D returns the decimal (zero if NULL)
+func (d NullDecimalOpt) D() decimal.Decimal {
+	if !d.Valid {
+		return decimal.Zero
+	}
+	return d.Decimal
+}
+
+// String returns the decimal or "" if NULL
+func (d NullDecimalOpt) String() string {
+	if !d.Valid {
+		return ""
+	}
+	return d.Decimal.String()
+}
+
+// Scan implements the Scanner interface.
+func (d *NullDecimalOpt) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*d = NullDecimalOpt{}
+		return nil
+	case decimal.Decimal:
+		*d = NullDecimalOpt{Decimal: v, Valid: true}
+		return nil
+	}
+	var dd decimal.Decimal
+	if err := dd.Scan(value); err != nil {
+		return err
+	}
+	*d = NullDecimalOpt{Decimal: dd, Valid: true}
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (d NullDecimalOpt) Value() (driver.Value, error) {
+	if !d.Valid {
+		return nil, nil
+	}
+	return d.Decimal.Value()
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (d *NullDecimalOpt) UnmarshalJSON(v []byte) error {
+	if len(v) == 0 || string(v) == "null" {
+		*d = NullDecimalOpt{}
+		return nil
+	}
+	var dd decimal.Decimal
+	if err := dd.UnmarshalJSON(v); err != nil {
+		return err
+	}
+	*d = NullDecimalOpt{Decimal: dd, Valid: true}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (d NullDecimalOpt) MarshalJSON() ([]byte, error) {
+	if !d.Valid {
+		return []byte("null"), nil
+	}
+	return d.Decimal.MarshalJSON()
+}
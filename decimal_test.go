@@ -0,0 +1,55 @@
+package sqltypes
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNullDecimalOpt(t *testing.T) {
+	var d NullDecimalOpt
+	v, err := d.Value()
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+	//
+	assert.NoError(t, d.Scan([]byte("10.50")))
+	assert.True(t, d.Valid)
+	assert.Equal(t, "10.5", d.String())
+	v, err = d.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "10.5", v)
+	//
+	assert.NoError(t, d.Scan(nil))
+	assert.False(t, d.Valid)
+	assert.True(t, d.D().Equal(decimal.Zero))
+	//
+	assert.NoError(t, d.Scan(int64(3)))
+	assert.Equal(t, "3", d.String())
+	assert.NoError(t, d.Scan(decimal.New(25, -1)))
+	assert.Equal(t, "2.5", d.String())
+	assert.Error(t, d.Scan("abc"))
+	//
+	var zero NullDecimalOpt
+	assert.NoError(t, zero.Scan("0"))
+	v, err = zero.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "0", v)
+}
+
+func TestNullDecimalOptJSON(t *testing.T) {
+	s := struct {
+		A NullDecimalOpt
+		B NullDecimalOpt
+	}{A: DecimalOpt(decimal.New(1234, -2))}
+	b, err := json.Marshal(s)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"A":"12.34","B":null}`, string(b))
+	//
+	s.A, s.B = NullDecimalOpt{}, DecimalOpt(decimal.New(1, 0))
+	assert.NoError(t, json.Unmarshal([]byte(`{"A":12.5,"B":null}`), &s))
+	assert.True(t, s.A.Valid)
+	assert.Equal(t, "12.5", s.A.String())
+	assert.False(t, s.B.Valid)
+}
@@ -0,0 +1,204 @@
+package sqltypes
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+
+	"github.com/shopspring/decimal"
+)
+
+// RoundingMode is how a NullDecimalPS is rounded to its scale
+type RoundingMode int
+
+const (
+	// RoundHalfEven rounds to the nearest, ties to the even digit (banker's)
+	RoundHalfEven RoundingMode = iota
+	// RoundHalfUp rounds to the nearest, ties away from zero (MySQL)
+	RoundHalfUp
+	// RoundDown rounds towards zero (truncates)
+	RoundDown
+	// RoundCeiling rounds towards positive infinity
+	RoundCeiling
+	// RoundFloor rounds towards negative infinity
+	RoundFloor
+	// RoundUnnecessary doesn't round: a value with more fractional digits
+	// than the scale is an error
+	RoundUnnecessary
+)
+
+func (m RoundingMode) String() string {
+	switch m {
+	case RoundHalfEven:
+		return "half-even"
+	case RoundHalfUp:
+		return "half-up"
+	case RoundDown:
+		return "down"
+	case RoundCeiling:
+		return "ceiling"
+	case RoundFloor:
+		return "floor"
+	case RoundUnnecessary:
+		return "unnecessary"
+	}
+	return "RoundingMode(" + strconv.Itoa(int(m)) + ")"
+}
+
+// DecimalSpec is the precision and scale of a DECIMAL(p,s) column
+type DecimalSpec struct {
+	// Precision is the total number of digits; 0 means unlimited
+	Precision int
+	// Scale is the number of fractional digits
+	Scale    int
+	Rounding RoundingMode
+}
+
+// Apply rounds d to the scale of s and checks that the integer part fits in
+// Precision - Scale digits.
+func (s DecimalSpec) Apply(d decimal.Decimal) (decimal.Decimal, error) {
+	if s.Scale < 0 || (s.Precision > 0 && s.Scale > s.Precision) {
+		return d, fmt.Errorf("invalid decimal spec (%d,%d)", s.Precision, s.Scale)
+	}
+	r, err := roundDecimal(d, s.Scale, s.Rounding)
+	if err != nil {
+		return d, err
+	}
+	if s.Precision > 0 {
+		if n := intDigits(r); n > s.Precision-s.Scale {
+			return d, fmt.Errorf("decimal '%s' overflows DECIMAL(%d,%d)", d.String(), s.Precision, s.Scale)
+		}
+	}
+	return r, nil
+}
+
+// roundDecimal rounds d to scale fractional digits
+func roundDecimal(d decimal.Decimal, scale int, mode RoundingMode) (decimal.Decimal, error) {
+	places := int32(scale)
+	var r decimal.Decimal
+	switch mode {
+	case RoundHalfEven:
+		r = d.RoundBank(places)
+	case RoundHalfUp:
+		r = d.Round(places)
+	case RoundDown:
+		r = d.Truncate(places)
+	case RoundCeiling:
+		r = d.Shift(places).Ceil().Shift(-places)
+	case RoundFloor:
+		r = d.Shift(places).Floor().Shift(-places)
+	case RoundUnnecessary:
+		r = d.Truncate(places)
+		if !r.Equal(d) {
+			return d, fmt.Errorf("decimal '%s' has more than %d fractional digits", d.String(), scale)
+		}
+	default:
+		return d, fmt.Errorf("invalid rounding mode %v", mode)
+	}
+	return r, nil
+}
+
+// intDigits returns the number of digits of the integer part of d
+func intDigits(d decimal.Decimal) int {
+	s := d.Abs().Truncate(0).String()
+	if s == "0" {
+		return 0
+	}
+	return len(s)
+}
+
+// NullDecimalPS is a decimal stored in a DECIMAL(p,s) column. Value,
+// MarshalJSON and UnmarshalJSON round it to Spec.Scale and return an error if
+// the integer part overflows Spec.Precision. Scan stores the value as read
+// and keeps Spec. With a nil Spec the value is never rounded. Valid = false
+// is NULL.
+type NullDecimalPS struct {
+	Decimal decimal.Decimal
+	Valid   bool
+	Spec    *DecimalSpec
+}
+
+// DecimalPS returns a valid NullDecimalPS
+func DecimalPS(v decimal.Decimal, spec DecimalSpec) NullDecimalPS {
+	return NullDecimalPS{Decimal: v, Valid: true, Spec: &spec}
+}
+
+// Rounded returns the decimal rounded to the spec
+func (d NullDecimalPS) Rounded() (decimal.Decimal, error) {
+	if d.Spec == nil {
+		return d.Decimal, nil
+	}
+	return d.Spec.Apply(d.Decimal)
+}
+
+// format writes r, already rounded, with Spec.Scale fractional digits
+func (d NullDecimalPS) format(r decimal.Decimal) string {
+	if d.Spec == nil {
+		return r.String()
+	}
+	return r.StringFixed(int32(d.Spec.Scale))
+}
+
+// String returns the decimal with Spec.Scale fractional digits, "" if NULL
+// or if it doesn't fit the spec
+func (d NullDecimalPS) String() string {
+	if !d.Valid {
+		return ""
+	}
+	r, err := d.Rounded()
+	if err != nil {
+		return ""
+	}
+	return d.format(r)
+}
+
+// Scan implements the Scanner interface.
+func (d *NullDecimalPS) Scan(value interface{}) error {
+	var o NullDecimalOpt
+	if err := o.Scan(value); err != nil {
+		return err
+	}
+	d.Decimal, d.Valid = o.Decimal, o.Valid
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (d NullDecimalPS) Value() (driver.Value, error) {
+	if !d.Valid {
+		return nil, nil
+	}
+	r, err := d.Rounded()
+	if err != nil {
+		return nil, err
+	}
+	return d.format(r), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (d *NullDecimalPS) UnmarshalJSON(v []byte) error {
+	var o NullDecimalOpt
+	if err := o.UnmarshalJSON(v); err != nil {
+		return err
+	}
+	if o.Valid && d.Spec != nil {
+		r, err := d.Spec.Apply(o.Decimal)
+		if err != nil {
+			return err
+		}
+		o.Decimal = r
+	}
+	d.Decimal, d.Valid = o.Decimal, o.Valid
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (d NullDecimalPS) MarshalJSON() ([]byte, error) {
+	if !d.Valid {
+		return []byte("null"), nil
+	}
+	r, err := d.Rounded()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(strconv.Quote(d.format(r))), nil
+}
@@ -0,0 +1,133 @@
+package sqltypes
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecimalSpecRounding(t *testing.T) {
+	tests := []struct {
+		in   string
+		mode RoundingMode
+		out  string
+	}{
+		{"1.005", RoundHalfEven, "1.00"},
+		{"1.015", RoundHalfEven, "1.02"},
+		{"-1.005", RoundHalfEven, "-1.00"},
+		{"1.005", RoundHalfUp, "1.01"},
+		{"-1.005", RoundHalfUp, "-1.01"},
+		{"1.009", RoundDown, "1.00"},
+		{"-1.009", RoundDown, "-1.00"},
+		{"1.001", RoundCeiling, "1.01"},
+		{"-1.009", RoundCeiling, "-1.00"},
+		{"1.009", RoundFloor, "1.00"},
+		{"-1.001", RoundFloor, "-1.01"},
+		{"1.5", RoundUnnecessary, "1.50"},
+	}
+	for _, tt := range tests {
+		r, err := DecimalSpec{12, 2, tt.mode}.Apply(decimal.RequireFromString(tt.in))
+		assert.NoError(t, err, "%s %v", tt.in, tt.mode)
+		assert.Equal(t, tt.out, r.StringFixed(2), "%s %v", tt.in, tt.mode)
+	}
+	_, err := DecimalSpec{12, 2, RoundUnnecessary}.Apply(decimal.RequireFromString("1.001"))
+	assert.Error(t, err)
+}
+
+func TestDecimalSpecOverflow(t *testing.T) {
+	spec := DecimalSpec{Precision: 5, Scale: 2}
+	_, err := spec.Apply(decimal.RequireFromString("999.99"))
+	assert.NoError(t, err)
+	_, err = spec.Apply(decimal.RequireFromString("-999.99"))
+	assert.NoError(t, err)
+	_, err = spec.Apply(decimal.RequireFromString("1000"))
+	assert.Error(t, err)
+	// rounding can carry into the integer part
+	_, err = spec.Apply(decimal.RequireFromString("999.995"))
+	assert.Error(t, err)
+	_, err = spec.Apply(decimal.RequireFromString("0.001"))
+	assert.NoError(t, err)
+	_, err = DecimalSpec{Precision: 2, Scale: 3}.Apply(decimal.Zero)
+	assert.Error(t, err)
+	// unlimited precision
+	_, err = DecimalSpec{Scale: 2}.Apply(decimal.RequireFromString("123456789012345678901234567890"))
+	assert.NoError(t, err)
+}
+
+func TestNullDecimalPS(t *testing.T) {
+	spec := DecimalSpec{Precision: 12, Scale: 2, Rounding: RoundHalfUp}
+	d := DecimalPS(decimal.RequireFromString("10.125"), spec)
+	v, err := d.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "10.13", v)
+	assert.Equal(t, "10.13", d.String())
+	//
+	d = DecimalPS(decimal.RequireFromString("12345678901"), spec)
+	_, err = d.Value()
+	assert.Error(t, err)
+	//
+	d = NullDecimalPS{Spec: &spec}
+	v, err = d.Value()
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+	assert.NoError(t, d.Scan([]byte("3.14159")))
+	assert.True(t, d.Valid)
+	assert.Equal(t, &spec, d.Spec)
+	assert.Equal(t, "3.14159", d.Decimal.String())
+	assert.NoError(t, d.Scan(nil))
+	assert.False(t, d.Valid)
+	assert.Equal(t, &spec, d.Spec)
+}
+
+func TestNullDecimalPSJSON(t *testing.T) {
+	s := struct {
+		Price NullDecimalPS `json:"price"`
+	}{Price: NullDecimalPS{Spec: &DecimalSpec{Precision: 5, Scale: 2, Rounding: RoundFloor}}}
+	assert.NoError(t, json.Unmarshal([]byte(`{"price":"12.349"}`), &s))
+	assert.Equal(t, "12.34", s.Price.Decimal.String())
+	b, err := json.Marshal(s)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"price":"12.34"}`, string(b))
+	//
+	assert.Error(t, json.Unmarshal([]byte(`{"price":1000}`), &s))
+	assert.NoError(t, json.Unmarshal([]byte(`{"price":null}`), &s))
+	assert.False(t, s.Price.Valid)
+	b, err = json.Marshal(s)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"price":null}`, string(b))
+}
+
+func TestNullDecimalPSZeroSpec(t *testing.T) {
+	var d NullDecimalPS
+	assert.NoError(t, d.Scan("3.14"))
+	v, err := d.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "3.14", v)
+	assert.Equal(t, "3.14", d.String())
+
+	var j NullDecimalPS
+	assert.NoError(t, json.Unmarshal([]byte(`"3.14"`), &j))
+	assert.True(t, j.Decimal.Equal(decimal.RequireFromString("3.14")))
+	b, err := json.Marshal(j)
+	assert.NoError(t, err)
+	assert.Equal(t, `"3.14"`, string(b))
+}
+
+func TestDecimalSpecScaleZero(t *testing.T) {
+	r, err := DecimalSpec{}.Apply(decimal.RequireFromString("2.7"))
+	assert.NoError(t, err)
+	assert.Equal(t, "3", r.String())
+	r, err = DecimalSpec{}.Apply(decimal.RequireFromString("2.5"))
+	assert.NoError(t, err)
+	assert.Equal(t, "2", r.String())
+	r, err = DecimalSpec{Rounding: RoundHalfUp}.Apply(decimal.RequireFromString("2.5"))
+	assert.NoError(t, err)
+	assert.Equal(t, "3", r.String())
+	//
+	d := DecimalPS(decimal.RequireFromString("2.7"), DecimalSpec{})
+	v, err := d.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "3", v)
+}
@@ -0,0 +1,529 @@
+package sqltypes
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DurationFormat selects how NullDuration and NullInterval are written to the
+// database.
+type DurationFormat int
+
+const (
+	// DurationDefault is DurationMySQL for NullDuration and DurationPostgres
+	// for NullInterval
+	DurationDefault DurationFormat = iota
+	// DurationMySQL is the MySQL TIME format: "-838:59:59.000000"
+	DurationMySQL
+	// DurationPostgres is the Postgres interval format: "1 year 2 mons 3 days 04:05:06"
+	DurationPostgres
+	// DurationISO8601 is the ISO 8601 duration format: "P1Y2M3DT4H5M6S"
+	DurationISO8601
+)
+
+// maxMySQLTime is the largest magnitude of a MySQL TIME value
+const maxMySQLTime = 838*time.Hour + 59*time.Minute + 59*time.Second
+
+// NullDuration is a signed duration for MySQL TIME and Postgres INTERVAL
+// columns (Valid = false is NULL). Intervals with years or months can't be
+// scanned into a NullDuration; use NullInterval instead.
+type NullDuration struct {
+	Duration time.Duration
+	Valid    bool
+	// Format is the format written by Value
+	Format DurationFormat
+}
+
+// Scan implements the Scanner interface.
+func (d *NullDuration) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		d.Duration, d.Valid = 0, false
+		return nil
+	case []byte:
+		return d.parse(string(v))
+	case string:
+		return d.parse(v)
+	}
+	return fmt.Errorf("unsupported Scan, storing driver.Value type %T into type %T", value, d)
+}
+
+func (d *NullDuration) parse(s string) error {
+	iv, err := parseInterval(s)
+	if err != nil {
+		return err
+	}
+	if iv.months != 0 {
+		return fmt.Errorf("invalid duration '%s': months can't be converted to a duration", s)
+	}
+	d.Duration = time.Duration(iv.days)*oneDay + time.Duration(iv.nanos)
+	d.Valid = true
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (d NullDuration) Value() (driver.Value, error) {
+	if !d.Valid {
+		return nil, nil
+	}
+	if d.Format == DurationDefault {
+		d.Format = DurationMySQL
+	}
+	if d.Format == DurationMySQL && (d.Duration > maxMySQLTime || d.Duration < -maxMySQLTime) {
+		return nil, fmt.Errorf("duration %v out of MySQL TIME range", d.Duration)
+	}
+	return formatInterval(interval{nanos: int64(d.Duration)}, d.Format), nil
+}
+
+// String returns the duration in ISO 8601 format or "" if NULL.
+func (d NullDuration) String() string {
+	if !d.Valid {
+		return ""
+	}
+	return formatInterval(interval{nanos: int64(d.Duration)}, DurationISO8601)
+}
+
+// MarshalJSON implements json.Marshaler
+func (d NullDuration) MarshalJSON() ([]byte, error) {
+	if !d.Valid {
+		return []byte("null"), nil
+	}
+	return []byte(strconv.Quote(d.String())), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (d *NullDuration) UnmarshalJSON(v []byte) error {
+	if len(v) == 0 || string(v) == "null" {
+		d.Duration, d.Valid = 0, false
+		return nil
+	}
+	s, err := strconv.Unquote(string(v))
+	if err != nil {
+		return fmt.Errorf("invalid duration %s", string(v))
+	}
+	return d.parse(s)
+}
+
+// NullInterval is a Postgres INTERVAL with separate months, days and
+// microseconds components (Valid = false is NULL).
+type NullInterval struct {
+	Months       int32
+	Days         int32
+	Microseconds int64
+	Valid        bool
+	// Format is the format written by Value
+	Format DurationFormat
+}
+
+// AddTo returns t shifted by the interval, applying months (clamped to the
+// end of the month), days and microseconds in that order like Postgres does.
+func (i NullInterval) AddTo(t time.Time) time.Time {
+	if !i.Valid {
+		return t
+	}
+	yy, mm, dd := t.Date()
+	first := time.Date(yy, mm+time.Month(i.Months), 1, 0, 0, 0, 0, time.UTC)
+	if last := first.AddDate(0, 1, -1).Day(); dd > last {
+		dd = last
+	}
+	t = time.Date(first.Year(), first.Month(), dd, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	return t.AddDate(0, 0, int(i.Days)).Add(time.Duration(i.Microseconds) * time.Microsecond)
+}
+
+// Scan implements the Scanner interface.
+func (i *NullInterval) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		i.Months, i.Days, i.Microseconds, i.Valid = 0, 0, 0, false
+		return nil
+	case []byte:
+		return i.parse(string(v))
+	case string:
+		return i.parse(v)
+	}
+	return fmt.Errorf("unsupported Scan, storing driver.Value type %T into type %T", value, i)
+}
+
+func (i *NullInterval) parse(s string) error {
+	iv, err := parseInterval(s)
+	if err != nil {
+		return err
+	}
+	i.Months = int32(iv.months)
+	i.Days = int32(iv.days)
+	i.Microseconds = iv.nanos / 1000
+	i.Valid = true
+	return nil
+}
+
+func (i NullInterval) interval() interval {
+	return interval{
+		months: int64(i.Months),
+		days:   int64(i.Days),
+		nanos:  i.Microseconds * 1000,
+	}
+}
+
+// Value implements the driver Valuer interface.
+func (i NullInterval) Value() (driver.Value, error) {
+	if !i.Valid {
+		return nil, nil
+	}
+	switch i.Format {
+	case DurationDefault:
+		i.Format = DurationPostgres
+	case DurationMySQL:
+		if i.Months != 0 {
+			return nil, fmt.Errorf("interval with months can't be written in MySQL TIME format")
+		}
+	}
+	return formatInterval(i.interval(), i.Format), nil
+}
+
+// String returns the interval in ISO 8601 format or "" if NULL.
+func (i NullInterval) String() string {
+	if !i.Valid {
+		return ""
+	}
+	return formatInterval(i.interval(), DurationISO8601)
+}
+
+// MarshalJSON implements json.Marshaler
+func (i NullInterval) MarshalJSON() ([]byte, error) {
+	if !i.Valid {
+		return []byte("null"), nil
+	}
+	return []byte(strconv.Quote(i.String())), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (i *NullInterval) UnmarshalJSON(v []byte) error {
+	if len(v) == 0 || string(v) == "null" {
+		i.Months, i.Days, i.Microseconds, i.Valid = 0, 0, 0, false
+		return nil
+	}
+	s, err := strconv.Unquote(string(v))
+	if err != nil {
+		return fmt.Errorf("invalid interval %s", string(v))
+	}
+	return i.parse(s)
+}
+
+// interval is the parsed form shared by NullDuration and NullInterval
+type interval struct {
+	months int64
+	days   int64
+	nanos  int64
+}
+
+// parseInterval accepts the MySQL TIME format ("[-][D ]hhh:mm:ss[.f]"),
+// the Postgres format ("1 year -2 mons 3 days 04:05:06[ ago]") and
+// ISO 8601 durations ("-P1Y2M3W4DT5H6M7.5S").
+func parseInterval(s string) (interval, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return interval{}, fmt.Errorf("invalid interval ''")
+	}
+	t := strings.TrimLeft(s, "+-")
+	if len(t) > 0 && (t[0] == 'P' || t[0] == 'p') {
+		return parseISO8601Interval(s)
+	}
+	var iv interval
+	fields := strings.Fields(s)
+	neg := false
+	if fields[len(fields)-1] == "ago" {
+		neg = true
+		fields = fields[:len(fields)-1]
+	}
+	if len(fields) > 0 && fields[0] == "@" {
+		fields = fields[1:]
+	}
+	if len(fields) == 0 {
+		return interval{}, fmt.Errorf("invalid interval '%s'", s)
+	}
+	for k := 0; k < len(fields); k++ {
+		f := fields[k]
+		if strings.Contains(f, ":") {
+			ns, err := parseClock(f)
+			if err != nil {
+				return interval{}, fmt.Errorf("invalid interval '%s': %v", s, err)
+			}
+			iv.nanos += ns
+			continue
+		}
+		unit := ""
+		if k+1 < len(fields) && !strings.Contains(fields[k+1], ":") {
+			k++
+			unit = strings.ToLower(fields[k])
+		} else if k+1 < len(fields) {
+			// MySQL "D hh:mm:ss"
+			unit = "day"
+		}
+		if unit == "" {
+			return interval{}, fmt.Errorf("invalid interval '%s': missing unit after %s", s, f)
+		}
+		n, err := strconv.ParseInt(f, 10, 64)
+		if err != nil {
+			return interval{}, fmt.Errorf("invalid interval '%s': %v", s, strconvErr(err))
+		}
+		if err := iv.add(n, unit); err != nil {
+			return interval{}, fmt.Errorf("invalid interval '%s': %v", s, err)
+		}
+	}
+	if neg {
+		iv = interval{-iv.months, -iv.days, -iv.nanos}
+	}
+	return iv, nil
+}
+
+func (iv *interval) add(n int64, unit string) error {
+	switch strings.TrimSuffix(unit, "s") {
+	case "year":
+		iv.months += n * 12
+	case "mon", "month":
+		iv.months += n
+	case "week":
+		iv.days += n * 7
+	case "day":
+		iv.days += n
+	case "hour":
+		iv.nanos += n * int64(time.Hour)
+	case "min", "minute":
+		iv.nanos += n * int64(time.Minute)
+	case "sec", "second":
+		iv.nanos += n * int64(time.Second)
+	case "msec", "millisecond":
+		iv.nanos += n * int64(time.Millisecond)
+	case "usec", "microsecond":
+		iv.nanos += n * int64(time.Microsecond)
+	default:
+		return fmt.Errorf("unknown unit '%s'", unit)
+	}
+	return nil
+}
+
+// parseClock parses "[+-]h+:mm[:ss[.fffffffff]]" into nanoseconds
+func parseClock(s string) (int64, error) {
+	neg := false
+	switch {
+	case strings.HasPrefix(s, "-"):
+		neg = true
+		s = s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+	frac := ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		s, frac = s[:i], s[i+1:]
+	}
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid time '%s'", s)
+	}
+	var ns int64
+	mul := int64(time.Hour)
+	for i, p := range parts {
+		n, err := strconv.ParseInt(p, 10, 64)
+		if err != nil || n < 0 || (i > 0 && (len(p) != 2 || n > 59)) {
+			return 0, fmt.Errorf("invalid time '%s'", s)
+		}
+		ns += n * mul
+		mul /= 60
+	}
+	f, err := parseFraction(frac)
+	if err != nil {
+		return 0, err
+	}
+	ns += f
+	if neg {
+		ns = -ns
+	}
+	return ns, nil
+}
+
+// parseFraction parses the digits after the decimal point of a second
+// into nanoseconds
+func parseFraction(frac string) (int64, error) {
+	if frac == "" {
+		return 0, nil
+	}
+	if len(frac) > 9 {
+		frac = frac[:9]
+	}
+	n, err := strconv.ParseInt(frac+strings.Repeat("0", 9-len(frac)), 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid fraction '%s'", frac)
+	}
+	return n, nil
+}
+
+func parseISO8601Interval(s string) (interval, error) {
+	var iv interval
+	orig := s
+	neg := false
+	switch s[0] {
+	case '-':
+		neg = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+	s = strings.ToUpper(s[1:])
+	if s == "" {
+		return interval{}, fmt.Errorf("invalid interval '%s'", orig)
+	}
+	inTime := false
+	for len(s) > 0 {
+		if s[0] == 'T' {
+			if inTime {
+				return interval{}, fmt.Errorf("invalid interval '%s'", orig)
+			}
+			inTime = true
+			s = s[1:]
+			continue
+		}
+		i := strings.IndexAny(s, "YMWDHS")
+		if i <= 0 {
+			return interval{}, fmt.Errorf("invalid interval '%s'", orig)
+		}
+		num, des := strings.Replace(s[:i], ",", ".", 1), s[i]
+		s = s[i+1:]
+		frac := ""
+		if j := strings.IndexByte(num, '.'); j >= 0 {
+			if des != 'S' {
+				return interval{}, fmt.Errorf("invalid interval '%s': fractions are only allowed in seconds", orig)
+			}
+			num, frac = num[:j], num[j+1:]
+		}
+		n, err := strconv.ParseInt(num, 10, 64)
+		if err != nil {
+			return interval{}, fmt.Errorf("invalid interval '%s'", orig)
+		}
+		switch {
+		case !inTime && des == 'Y':
+			iv.months += n * 12
+		case !inTime && des == 'M':
+			iv.months += n
+		case !inTime && des == 'W':
+			iv.days += n * 7
+		case !inTime && des == 'D':
+			iv.days += n
+		case inTime && des == 'H':
+			iv.nanos += n * int64(time.Hour)
+		case inTime && des == 'M':
+			iv.nanos += n * int64(time.Minute)
+		case inTime && des == 'S':
+			f, err := parseFraction(frac)
+			if err != nil {
+				return interval{}, fmt.Errorf("invalid interval '%s'", orig)
+			}
+			if strings.HasPrefix(num, "-") {
+				f = -f
+			}
+			iv.nanos += n*int64(time.Second) + f
+		default:
+			return interval{}, fmt.Errorf("invalid interval '%s'", orig)
+		}
+	}
+	if neg {
+		iv = interval{-iv.months, -iv.days, -iv.nanos}
+	}
+	return iv, nil
+}
+
+func formatInterval(iv interval, f DurationFormat) string {
+	switch f {
+	case DurationPostgres:
+		parts := make([]string, 0, 4)
+		years, months := iv.months/12, iv.months%12
+		if years != 0 {
+			parts = append(parts, plural(years, "year"))
+		}
+		if months != 0 {
+			parts = append(parts, plural(months, "mon"))
+		}
+		if iv.days != 0 {
+			parts = append(parts, plural(iv.days, "day"))
+		}
+		if iv.nanos != 0 || len(parts) == 0 {
+			parts = append(parts, formatClock(iv.nanos, 6))
+		}
+		return strings.Join(parts, " ")
+	case DurationISO8601:
+		return formatISO8601(iv)
+	}
+	// MySQL TIME has no days
+	return formatClock(iv.days*int64(oneDay)+iv.nanos, 6)
+}
+
+func plural(n int64, unit string) string {
+	if n == 1 || n == -1 {
+		return strconv.FormatInt(n, 10) + " " + unit
+	}
+	return strconv.FormatInt(n, 10) + " " + unit + "s"
+}
+
+// formatClock formats ns as "[-]hh:mm:ss[.f]" with at most digits
+// fractional digits
+func formatClock(ns int64, digits int) string {
+	sign := ""
+	if ns < 0 {
+		sign = "-"
+		ns = -ns
+	}
+	d := time.Duration(ns)
+	s := fmt.Sprintf("%s%02d:%02d:%02d", sign, int64(d/time.Hour), int64(d/time.Minute%60), int64(d/time.Second%60))
+	return s + formatFraction(ns%int64(time.Second), digits)
+}
+
+// formatFraction returns ".fff" for the nanoseconds ns, truncated to digits
+// and without trailing zeros, or "" if there is no fraction
+func formatFraction(ns int64, digits int) string {
+	f := strings.TrimRight(fmt.Sprintf("%09d", ns)[:digits], "0")
+	if f == "" {
+		return ""
+	}
+	return "." + f
+}
+
+func formatISO8601(iv interval) string {
+	var b strings.Builder
+	b.WriteString("P")
+	years, months := iv.months/12, iv.months%12
+	if years != 0 {
+		b.WriteString(strconv.FormatInt(years, 10) + "Y")
+	}
+	if months != 0 {
+		b.WriteString(strconv.FormatInt(months, 10) + "M")
+	}
+	if iv.days != 0 {
+		b.WriteString(strconv.FormatInt(iv.days, 10) + "D")
+	}
+	if iv.nanos == 0 {
+		if b.Len() == 1 {
+			return "PT0S"
+		}
+		return b.String()
+	}
+	b.WriteString("T")
+	ns := iv.nanos
+	sign := ""
+	if ns < 0 {
+		sign = "-"
+		ns = -ns
+	}
+	d := time.Duration(ns)
+	if h := int64(d / time.Hour); h != 0 {
+		fmt.Fprintf(&b, "%s%dH", sign, h)
+	}
+	if m := int64(d / time.Minute % 60); m != 0 {
+		fmt.Fprintf(&b, "%s%dM", sign, m)
+	}
+	if sec, frac := int64(d/time.Second%60), ns%int64(time.Second); sec != 0 || frac != 0 {
+		fmt.Fprintf(&b, "%s%d%sS", sign, sec, formatFraction(frac, 9))
+	}
+	return b.String()
+}
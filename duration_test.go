@@ -0,0 +1,88 @@
+package sqltypes
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNullDurationScan(t *testing.T) {
+	var d NullDuration
+	assert.NoError(t, d.Scan("-838:59:59"))
+	assert.Equal(t, -maxMySQLTime, d.Duration)
+	assert.NoError(t, d.Scan([]byte("12:30:00.5")))
+	assert.Equal(t, 12*time.Hour+30*time.Minute+500*time.Millisecond, d.Duration)
+	assert.NoError(t, d.Scan("1 day 02:03:04"))
+	assert.Equal(t, 26*time.Hour+3*time.Minute+4*time.Second, d.Duration)
+	assert.NoError(t, d.Scan("-1 days +02:00:00"))
+	assert.Equal(t, -22*time.Hour, d.Duration)
+	assert.NoError(t, d.Scan("P1DT2H3M4S"))
+	assert.Equal(t, 26*time.Hour+3*time.Minute+4*time.Second, d.Duration)
+	assert.NoError(t, d.Scan("-PT1.25S"))
+	assert.Equal(t, -1250*time.Millisecond, d.Duration)
+	assert.NoError(t, d.Scan("3 hours 20 mins ago"))
+	assert.Equal(t, -(3*time.Hour + 20*time.Minute), d.Duration)
+	assert.Error(t, d.Scan("1 mon"))
+	assert.Error(t, d.Scan("P1Y"))
+	assert.Error(t, d.Scan("12:60:00"))
+	assert.Error(t, d.Scan("3 fortnights"))
+	assert.NoError(t, d.Scan(nil))
+	assert.False(t, d.Valid)
+}
+
+func TestNullDurationValue(t *testing.T) {
+	d := Duration(-(26*time.Hour + 3*time.Minute + 4*time.Second + 500*time.Microsecond))
+	v, err := d.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "-26:03:04.0005", v)
+	d.Format = DurationPostgres
+	v, err = d.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "-26:03:04.0005", v)
+	d.Format = DurationISO8601
+	v, err = d.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "PT-26H-3M-4.0005S", v)
+	_, err = Duration(839 * time.Hour).Value()
+	assert.Error(t, err)
+	v, err = NullDuration{}.Value()
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+}
+
+func TestNullDurationJSON(t *testing.T) {
+	str := struct {
+		A NullDuration `json:"a"`
+		B NullDuration `json:"b"`
+	}{}
+	assert.NoError(t, json.Unmarshal([]byte(`{"a":"01:30:00","b":null}`), &str))
+	assert.Equal(t, 90*time.Minute, str.A.Duration)
+	bb, err := json.Marshal(str)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a":"PT1H30M","b":null}`, string(bb))
+}
+
+func TestNullInterval(t *testing.T) {
+	var i NullInterval
+	assert.NoError(t, i.Scan("1 year 2 mons -3 days 04:05:06.789"))
+	assert.Equal(t, int32(14), i.Months)
+	assert.Equal(t, int32(-3), i.Days)
+	assert.Equal(t, int64(4*3600+5*60+6)*1000000+789000, i.Microseconds)
+	v, err := i.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "1 year 2 mons -3 days 04:05:06.789", v)
+	assert.Equal(t, "P1Y2M-3DT4H5M6.789S", i.String())
+	//
+	assert.NoError(t, i.Scan("P2W"))
+	assert.Equal(t, int32(14), i.Days)
+	assert.Equal(t, time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC),
+		NullInterval{Months: 1, Days: 1, Valid: true}.AddTo(time.Date(2020, 1, 31, 0, 0, 0, 0, time.UTC)))
+	//
+	bb, err := json.Marshal(NullInterval{Months: 1, Valid: true})
+	assert.NoError(t, err)
+	assert.Equal(t, `"P1M"`, string(bb))
+	_, err = NullInterval{Months: 1, Valid: true, Format: DurationMySQL}.Value()
+	assert.Error(t, err)
+}
@@ -0,0 +1,279 @@
+package sqltypes
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// NonFinitePolicy is how NaN and infinities are written to the database
+type NonFinitePolicy int
+
+const (
+	// NonFinitePassThrough writes NaN and infinities as they are (Postgres
+	// accepts them)
+	NonFinitePassThrough NonFinitePolicy = iota
+	// NonFiniteNull writes NaN and infinities as NULL
+	NonFiniteNull
+	// NonFiniteError makes Value return an error (MySQL rejects them)
+	NonFiniteError
+)
+
+// FloatPolicy is how a NullFloat64Opt or NullFloat32Opt handles NaN,
+// infinities and precision loss. The zero FloatPolicy passes NaN and
+// infinities through, marshals them as null and doesn't check precision.
+type FloatPolicy struct {
+	// NonFinite is how NaN and infinities are written to the database
+	NonFinite NonFinitePolicy
+	// NonFiniteJSONString marshals NaN and infinities as the strings "NaN",
+	// "Infinity" and "-Infinity" instead of null
+	NonFiniteJSONString bool
+	// CheckPrecision makes a float32 return an error when scanning a decimal
+	// string it can't represent ("16777217")
+	CheckPrecision bool
+}
+
+// NullFloat32 is a float32 with the 0 value being nil (on sending to sql).
+// Like NullFloat64, NaN and infinities pass through; use NullFloat32Opt to
+// choose or to check precision.
+type NullFloat32 float32
+
+// Scan implements the Scanner interface.
+func (n *NullFloat32) Scan(value interface{}) error {
+	f, _, err := scanFloat(value, 32, false)
+	if err != nil {
+		return err
+	}
+	*n = NullFloat32(f)
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (n NullFloat32) Value() (driver.Value, error) {
+	if n == 0 {
+		return nil, nil
+	}
+	return floatValue(float32Value(float32(n)), NonFinitePassThrough)
+}
+
+// MarshalJSON implements json.Marshaler
+func (n NullFloat32) MarshalJSON() ([]byte, error) {
+	return marshalFloat(float64(n), 32, false)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (n *NullFloat32) UnmarshalJSON(v []byte) error {
+	if len(v) == 0 || string(v) == "null" {
+		return nil
+	}
+	f, err := unmarshalFloat(v, 32, false)
+	if err != nil {
+		return err
+	}
+	*n = NullFloat32(f)
+	return nil
+}
+
+// NullFloat64Opt is a float64 where 0 is a valid value, written following
+// Policy. Valid = false is NULL.
+type NullFloat64Opt struct {
+	Float64 float64
+	Valid   bool
+	Policy  FloatPolicy
+}
+
+// Scan implements the Scanner interface.
+func (n *NullFloat64Opt) Scan(value interface{}) error {
+	f, valid, err := scanFloat(value, 64, false)
+	if err != nil {
+		return err
+	}
+	n.Float64, n.Valid = f, valid
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (n NullFloat64Opt) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return floatValue(n.Float64, n.Policy.NonFinite)
+}
+
+// MarshalJSON implements json.Marshaler
+func (n NullFloat64Opt) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return marshalFloat(n.Float64, 64, n.Policy.NonFiniteJSONString)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (n *NullFloat64Opt) UnmarshalJSON(v []byte) error {
+	if len(v) == 0 || string(v) == "null" {
+		n.Float64, n.Valid = 0, false
+		return nil
+	}
+	f, err := unmarshalFloat(v, 64, false)
+	if err != nil {
+		return err
+	}
+	n.Float64, n.Valid = f, true
+	return nil
+}
+
+// NullFloat32Opt is a float32 where 0 is a valid value, scanned and written
+// following Policy. Valid = false is NULL.
+type NullFloat32Opt struct {
+	Float32 float32
+	Valid   bool
+	Policy  FloatPolicy
+}
+
+// Scan implements the Scanner interface.
+func (n *NullFloat32Opt) Scan(value interface{}) error {
+	f, valid, err := scanFloat(value, 32, n.Policy.CheckPrecision)
+	if err != nil {
+		return err
+	}
+	n.Float32, n.Valid = float32(f), valid
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (n NullFloat32Opt) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return floatValue(float32Value(n.Float32), n.Policy.NonFinite)
+}
+
+// MarshalJSON implements json.Marshaler
+func (n NullFloat32Opt) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return marshalFloat(float64(n.Float32), 32, n.Policy.NonFiniteJSONString)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (n *NullFloat32Opt) UnmarshalJSON(v []byte) error {
+	if len(v) == 0 || string(v) == "null" {
+		n.Float32, n.Valid = 0, false
+		return nil
+	}
+	f, err := unmarshalFloat(v, 32, n.Policy.CheckPrecision)
+	if err != nil {
+		return err
+	}
+	n.Float32, n.Valid = float32(f), true
+	return nil
+}
+
+// float32Value widens f so that 0.1 is written as 0.1 and not
+// 0.10000000149011612
+func float32Value(f float32) float64 {
+	if math.IsNaN(float64(f)) || math.IsInf(float64(f), 0) {
+		return float64(f)
+	}
+	v, _ := strconv.ParseFloat(strconv.FormatFloat(float64(f), 'g', -1, 32), 64)
+	return v
+}
+
+// scanFloat converts a driver value to a float of bitSize bits, accepting
+// the textual forms of NaN and infinities. It returns false for NULL.
+func scanFloat(value interface{}, bitSize int, checkPrecision bool) (float64, bool, error) {
+	var f float64
+	switch v := value.(type) {
+	case nil:
+		return 0, false, nil
+	case float64:
+		f = v
+	case float32:
+		f = float64(v)
+	case int64:
+		f = float64(v)
+	case []byte:
+		var err error
+		if f, err = parseFloat(string(v), bitSize, checkPrecision); err != nil {
+			return 0, false, err
+		}
+	case string:
+		var err error
+		if f, err = parseFloat(v, bitSize, checkPrecision); err != nil {
+			return 0, false, err
+		}
+	default:
+		return 0, false, fmt.Errorf("unsupported Scan, storing driver.Value type %T into a float%d", value, bitSize)
+	}
+	if bitSize == 32 {
+		f = float64(float32(f))
+	}
+	return f, true, nil
+}
+
+func parseFloat(s string, bitSize int, checkPrecision bool) (float64, error) {
+	t := strings.TrimSpace(s)
+	f, err := strconv.ParseFloat(t, bitSize)
+	if err != nil {
+		return 0, fmt.Errorf("invalid float '%s'", s)
+	}
+	if bitSize == 32 && checkPrecision && !math.IsNaN(f) && !math.IsInf(f, 0) {
+		if d, err := decimal.NewFromString(t); err == nil && !d.Equal(decimal.NewFromFloat32(float32(f))) {
+			return 0, fmt.Errorf("converting '%s' to float32 loses precision", s)
+		}
+	}
+	return f, nil
+}
+
+// floatValue returns the driver value of f following policy
+func floatValue(f float64, policy NonFinitePolicy) (driver.Value, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		switch policy {
+		case NonFiniteNull:
+			return nil, nil
+		case NonFiniteError:
+			return nil, fmt.Errorf("cannot write %v to the database", f)
+		}
+	}
+	return f, nil
+}
+
+// marshalFloat encodes f as a JSON number; NaN and infinities are null or,
+// with asString, a string
+func marshalFloat(f float64, bitSize int, asString bool) ([]byte, error) {
+	var s string
+	switch {
+	case math.IsNaN(f):
+		s = "NaN"
+	case math.IsInf(f, 1):
+		s = "Infinity"
+	case math.IsInf(f, -1):
+		s = "-Infinity"
+	case bitSize == 32:
+		return json.Marshal(float32(f))
+	default:
+		return json.Marshal(f)
+	}
+	if asString {
+		return []byte(`"` + s + `"`), nil
+	}
+	return []byte("null"), nil
+}
+
+// unmarshalFloat decodes a JSON number or string ("NaN", "Infinity", "1.5")
+func unmarshalFloat(v []byte, bitSize int, checkPrecision bool) (float64, error) {
+	s := string(v)
+	if s[0] == '"' {
+		var err error
+		if s, err = strconv.Unquote(s); err != nil {
+			return 0, fmt.Errorf("invalid float %s", string(v))
+		}
+	}
+	return parseFloat(s, bitSize, checkPrecision)
+}
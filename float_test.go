@@ -0,0 +1,146 @@
+package sqltypes
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNullFloatScanNonFinite(t *testing.T) {
+	var f NullFloat64
+	assert.NoError(t, f.Scan([]byte("NaN")))
+	assert.True(t, math.IsNaN(float64(f)))
+	assert.NoError(t, f.Scan("Infinity"))
+	assert.True(t, math.IsInf(float64(f), 1))
+	assert.NoError(t, f.Scan([]byte("-Infinity")))
+	assert.True(t, math.IsInf(float64(f), -1))
+	assert.NoError(t, f.Scan([]byte("1.5")))
+	assert.Equal(t, NullFloat64(1.5), f)
+	assert.NoError(t, f.Scan(int64(2)))
+	assert.Equal(t, NullFloat64(2), f)
+	assert.NoError(t, f.Scan(nil))
+	assert.Equal(t, NullFloat64(0), f)
+	assert.Error(t, f.Scan("abc"))
+	//
+	var f32 NullFloat32
+	assert.NoError(t, f32.Scan("-inf"))
+	assert.True(t, math.IsInf(float64(f32), -1))
+	assert.NoError(t, f32.Scan("nan"))
+	assert.True(t, math.IsNaN(float64(f32)))
+}
+
+func TestNullFloatNonFiniteValue(t *testing.T) {
+	nan, inf := NullFloat64(math.NaN()), NullFloat32(math.Inf(1))
+	v, err := nan.Value()
+	assert.NoError(t, err)
+	assert.True(t, math.IsNaN(v.(float64)))
+	v, err = inf.Value()
+	assert.NoError(t, err)
+	assert.True(t, math.IsInf(v.(float64), 1))
+	v, err = NullFloat64(0).Value()
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+	//
+	pg := Float64Opt(math.NaN(), FloatPolicy{})
+	v, err = pg.Value()
+	assert.NoError(t, err)
+	assert.True(t, math.IsNaN(v.(float64)))
+	//
+	null := Float32Opt(float32(math.Inf(1)), FloatPolicy{NonFinite: NonFiniteNull})
+	v, err = null.Value()
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+	//
+	mysql := FloatPolicy{NonFinite: NonFiniteError}
+	_, err = Float64Opt(math.NaN(), mysql).Value()
+	assert.Error(t, err)
+	_, err = Float32Opt(float32(math.Inf(-1)), mysql).Value()
+	assert.Error(t, err)
+	v, err = Float64Opt(1.5, mysql).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, 1.5, v)
+	v, err = Float64Opt(0, mysql).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, 0.0, v)
+	v, err = NullFloat64Opt{Policy: mysql}.Value()
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+}
+
+func TestNullFloatOptScan(t *testing.T) {
+	var f NullFloat64Opt
+	assert.NoError(t, f.Scan("0"))
+	assert.True(t, f.Valid)
+	assert.Equal(t, 0.0, f.Float64)
+	assert.NoError(t, f.Scan([]byte("NaN")))
+	assert.True(t, math.IsNaN(f.Float64))
+	assert.NoError(t, f.Scan(nil))
+	assert.False(t, f.Valid)
+	//
+	var f32 NullFloat32Opt
+	assert.NoError(t, f32.Scan(float64(0.1)))
+	assert.Equal(t, float32(0.1), f32.Float32)
+	assert.NoError(t, f32.Scan("-Infinity"))
+	assert.True(t, math.IsInf(float64(f32.Float32), -1))
+}
+
+func TestNullFloatJSON(t *testing.T) {
+	s := struct {
+		A NullFloat64
+		B NullFloat64
+		C NullFloat32
+		D NullFloat32
+	}{0.1, NullFloat64(math.NaN()), 0.1, NullFloat32(math.Inf(-1))}
+	b, err := json.Marshal(s)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"A":0.1,"B":null,"C":0.1,"D":null}`, string(b))
+	//
+	str := FloatPolicy{NonFiniteJSONString: true}
+	o := struct {
+		A, B NullFloat64Opt
+		C, D NullFloat32Opt
+		E    NullFloat64Opt
+	}{Float64Opt(0.1, str), Float64Opt(math.NaN(), str), Float32Opt(0.1, str), Float32Opt(float32(math.Inf(-1)), str), Float64Opt(math.Inf(1), FloatPolicy{})}
+	b, err = json.Marshal(o)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"A":0.1,"B":"NaN","C":0.1,"D":"-Infinity","E":null}`, string(b))
+	//
+	assert.NoError(t, json.Unmarshal(b, &s))
+	assert.Equal(t, NullFloat64(0.1), s.A)
+	assert.True(t, math.IsNaN(float64(s.B)))
+	assert.Equal(t, NullFloat32(0.1), s.C)
+	assert.True(t, math.IsInf(float64(s.D), -1))
+	assert.NoError(t, json.Unmarshal([]byte(`{"A":"2.5","B":null}`), &s))
+	assert.Equal(t, NullFloat64(2.5), s.A)
+	assert.Error(t, json.Unmarshal([]byte(`{"A":"x"}`), &s))
+	//
+	assert.NoError(t, json.Unmarshal([]byte(`{"A":0,"B":null,"D":"Infinity"}`), &o))
+	assert.True(t, o.A.Valid)
+	assert.Equal(t, 0.0, o.A.Float64)
+	assert.False(t, o.B.Valid)
+	assert.True(t, math.IsInf(float64(o.D.Float32), 1))
+}
+
+func TestNullFloat32Precision(t *testing.T) {
+	var f NullFloat32
+	assert.NoError(t, f.Scan("16777217"))
+	assert.Equal(t, NullFloat32(16777216), f)
+	//
+	c := NullFloat32Opt{Policy: FloatPolicy{CheckPrecision: true}}
+	assert.NoError(t, c.Scan([]byte("0.1")))
+	assert.Equal(t, float32(0.1), c.Float32)
+	assert.NoError(t, c.Scan([]byte("16777216")))
+	assert.Error(t, c.Scan([]byte("16777217")))
+	assert.Error(t, c.Scan("3.14159265358979"))
+	assert.Error(t, c.Scan("1e40"))
+	assert.Error(t, json.Unmarshal([]byte(`16777217`), &c))
+	//
+	v, err := NullFloat32(0.1).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, 0.1, v)
+	v, err = Float32Opt(0.1, FloatPolicy{}).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, 0.1, v)
+}
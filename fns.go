@@ -1,6 +1,9 @@
 package sqltypes
 
 import (
+	"math/big"
+	"time"
+
 	"github.com/shopspring/decimal"
 )
 
@@ -28,3 +31,95 @@ func IntM1(v int) NullIntM1 {
 func Decimal(v decimal.Decimal) NullDecimal {
 	return NullDecimal(v)
 }
+
+// BigInt returns a valid NullBigInt
+func BigInt(v *big.Int) NullBigInt {
+	return NullBigInt{Int: v, Valid: true}
+}
+
+// DecimalOpt returns a valid NullDecimalOpt
+func DecimalOpt(v decimal.Decimal) NullDecimalOpt {
+	return NullDecimalOpt{Decimal: v, Valid: true}
+}
+
+// Float64Opt returns a valid NullFloat64Opt
+func Float64Opt(v float64, policy FloatPolicy) NullFloat64Opt {
+	return NullFloat64Opt{Float64: v, Valid: true, Policy: policy}
+}
+
+// Float32Opt returns a valid NullFloat32Opt
+func Float32Opt(v float32, policy FloatPolicy) NullFloat32Opt {
+	return NullFloat32Opt{Float32: v, Valid: true, Policy: policy}
+}
+
+// TimeOffset returns a NullTimeOffset keeping the offset of t
+func TimeOffset(t time.Time) NullTimeOffset {
+	if t.IsZero() {
+		return NullTimeOffset{}
+	}
+	_, offset := t.Zone()
+	return NullTimeOffset{
+		UTC:    t.UTC(),
+		Offset: offset,
+	}
+}
+
+// TimeOfDay returns a valid NullTimeOfDay
+func TimeOfDay(hour, min, sec, nsec int) NullTimeOfDay {
+	return NullTimeOfDay{
+		Hour:       hour,
+		Minute:     min,
+		Second:     sec,
+		Nanosecond: nsec,
+		Valid:      true,
+	}
+}
+
+// Duration returns a valid NullDuration
+func Duration(d time.Duration) NullDuration {
+	return NullDuration{
+		Duration: d,
+		Valid:    true,
+	}
+}
+
+// Date returns a NullDate, normalizing out of range values like time.Date
+// does. It returns a NULL date if the year is outside 1-9999.
+func Date(year int, month time.Month, day int) NullDate {
+	return DateFromTime(time.Date(year, month, day, 0, 0, 0, 0, time.UTC))
+}
+
+// DateFromTime returns the date of t in its location
+func DateFromTime(t time.Time) NullDate {
+	if t.IsZero() {
+		return NullDate{}
+	}
+	yy, mm, dd := t.Date()
+	if yy < 1 || yy > 9999 {
+		return NullDate{}
+	}
+	return packDate(yy, int(mm), dd)
+}
+
+// DateFromString returns a NullDate from the "2006-01-02" form, or a NULL
+// date if s is invalid.
+func DateFromString(s string) NullDate {
+	var d NullDate
+	if err := d.strscan(s); err != nil {
+		return NullDate{}
+	}
+	return d
+}
+
+// TriBool returns TriTrue or TriFalse
+func TriBool(v bool) NullTriBool {
+	if v {
+		return TriTrue
+	}
+	return TriFalse
+}
+
+// JSON returns a valid NullJSON
+func JSON[T any](v T) NullJSON[T] {
+	return NullJSON[T]{V: v, Valid: true}
+}
@@ -0,0 +1,149 @@
+package sqltypes
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// NullJSON is a JSON document column (MySQL JSON, Postgres json and jsonb)
+// decoded into a T. When the containing struct is marshaled it is embedded
+// as JSON, not as a string. Valid = false is NULL.
+type NullJSON[T any] struct {
+	V     T
+	Valid bool
+	// JSONNull is set when the document is the JSON null (only with
+	// DistinctNull)
+	JSONNull bool
+	// DistinctNull keeps the JSON null document apart from SQL NULL: it is
+	// read as Valid with JSONNull set and written back as 'null'. Otherwise
+	// a 'null' document is read as NULL.
+	DistinctNull bool
+}
+
+func (j *NullJSON[T]) reset(valid, null bool) {
+	var zero T
+	j.V, j.Valid, j.JSONNull = zero, valid, null
+}
+
+func (j *NullJSON[T]) decode(b []byte) error {
+	if bytes.Equal(bytes.TrimSpace(b), []byte("null")) {
+		j.reset(j.DistinctNull, j.DistinctNull)
+		return nil
+	}
+	j.reset(false, false)
+	if err := json.Unmarshal(b, &j.V); err != nil {
+		return err
+	}
+	j.Valid = true
+	return nil
+}
+
+// Scan implements the Scanner interface.
+func (j *NullJSON[T]) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		j.reset(false, false)
+		return nil
+	case []byte:
+		return j.decode(v)
+	case string:
+		return j.decode([]byte(v))
+	}
+	return fmt.Errorf("unsupported Scan, storing driver.Value type %T into type %T", value, j)
+}
+
+// Value implements the driver Valuer interface. The document is written as
+// a string, as MySQL rejects JSON in binary strings.
+func (j NullJSON[T]) Value() (driver.Value, error) {
+	if !j.Valid {
+		return nil, nil
+	}
+	b, err := j.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (j NullJSON[T]) MarshalJSON() ([]byte, error) {
+	if !j.Valid || j.JSONNull {
+		return []byte("null"), nil
+	}
+	return json.Marshal(j.V)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (j *NullJSON[T]) UnmarshalJSON(v []byte) error {
+	if len(v) == 0 {
+		j.reset(false, false)
+		return nil
+	}
+	return j.decode(v)
+}
+
+// NullRawJSON is a JSON document column kept as bytes. Scan and
+// UnmarshalJSON check that the document is valid JSON. Valid = false is
+// NULL.
+type NullRawJSON struct {
+	JSON  json.RawMessage
+	Valid bool
+}
+
+func (r *NullRawJSON) set(b []byte) error {
+	if !json.Valid(b) {
+		return fmt.Errorf("invalid JSON '%s'", string(b))
+	}
+	// the driver may reuse b
+	r.JSON, r.Valid = append(json.RawMessage(nil), b...), true
+	return nil
+}
+
+// String returns the document or "" if NULL
+func (r NullRawJSON) String() string {
+	if !r.Valid {
+		return ""
+	}
+	return string(r.JSON)
+}
+
+// Scan implements the Scanner interface.
+func (r *NullRawJSON) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		r.JSON, r.Valid = nil, false
+		return nil
+	case []byte:
+		return r.set(v)
+	case string:
+		return r.set([]byte(v))
+	}
+	return fmt.Errorf("unsupported Scan, storing driver.Value type %T into type %T", value, r)
+}
+
+// Value implements the driver Valuer interface.
+func (r NullRawJSON) Value() (driver.Value, error) {
+	if !r.Valid {
+		return nil, nil
+	}
+	return string(r.JSON), nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (r NullRawJSON) MarshalJSON() ([]byte, error) {
+	if !r.Valid || len(r.JSON) == 0 {
+		return []byte("null"), nil
+	}
+	return r.JSON, nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (r *NullRawJSON) UnmarshalJSON(v []byte) error {
+	if len(v) == 0 || string(v) == "null" {
+		r.JSON, r.Valid = nil, false
+		return nil
+	}
+	return r.set(v)
+}
@@ -0,0 +1,103 @@
+package sqltypes
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type jsonDoc struct {
+	Name string   `json:"name"`
+	Tags []string `json:"tags"`
+}
+
+func TestNullJSONScanValue(t *testing.T) {
+	var j NullJSON[jsonDoc]
+	assert.NoError(t, j.Scan([]byte(`{"name":"a","tags":["x","y"]}`)))
+	assert.True(t, j.Valid)
+	assert.Equal(t, jsonDoc{"a", []string{"x", "y"}}, j.V)
+	v, err := j.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"name":"a","tags":["x","y"]}`, v)
+	// a new document replaces the previous one
+	assert.NoError(t, j.Scan(`{"name":"b"}`))
+	assert.Equal(t, jsonDoc{Name: "b"}, j.V)
+	//
+	assert.NoError(t, j.Scan(nil))
+	assert.False(t, j.Valid)
+	v, err = j.Value()
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+	assert.Error(t, j.Scan([]byte(`{"name":`)))
+	assert.Error(t, j.Scan(int64(1)))
+	//
+	var m NullJSON[map[string]int]
+	assert.NoError(t, m.Scan([]byte(`{"a":1}`)))
+	assert.NoError(t, m.Scan([]byte(`{"b":2}`)))
+	assert.Equal(t, map[string]int{"b": 2}, m.V)
+}
+
+func TestNullJSONNull(t *testing.T) {
+	var j NullJSON[*jsonDoc]
+	assert.NoError(t, j.Scan([]byte(" null ")))
+	assert.False(t, j.Valid)
+	assert.False(t, j.JSONNull)
+	//
+	j = NullJSON[*jsonDoc]{DistinctNull: true}
+	assert.NoError(t, j.Scan([]byte("null")))
+	assert.True(t, j.Valid)
+	assert.True(t, j.JSONNull)
+	v, err := j.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "null", v)
+	assert.NoError(t, j.Scan(nil))
+	assert.False(t, j.Valid)
+	assert.True(t, j.DistinctNull)
+	v, err = j.Value()
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+}
+
+func TestNullJSONEmbedded(t *testing.T) {
+	s := struct {
+		ID  int                 `json:"id"`
+		Doc NullJSON[jsonDoc]   `json:"doc"`
+		Nil NullJSON[jsonDoc]   `json:"nil"`
+		Raw NullRawJSON         `json:"raw"`
+		Any NullJSON[[]float64] `json:"any"`
+	}{ID: 1, Doc: JSON(jsonDoc{Name: "a"}), Any: JSON([]float64{1.5})}
+	assert.NoError(t, s.Raw.Scan([]byte(`{"k": [1, 2]}`)))
+	b, err := json.Marshal(s)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"id":1,"doc":{"name":"a","tags":null},"nil":null,"raw":{"k":[1,2]},"any":[1.5]}`, string(b))
+	//
+	s.Doc, s.Raw = NullJSON[jsonDoc]{}, NullRawJSON{}
+	assert.NoError(t, json.Unmarshal([]byte(`{"doc":{"name":"z"},"raw":[true],"nil":null}`), &s))
+	assert.Equal(t, "z", s.Doc.V.Name)
+	assert.True(t, s.Doc.Valid)
+	assert.Equal(t, `[true]`, s.Raw.String())
+	assert.False(t, s.Nil.Valid)
+}
+
+func TestNullRawJSON(t *testing.T) {
+	var r NullRawJSON
+	buf := []byte(`{"a":1}`)
+	assert.NoError(t, r.Scan(buf))
+	buf[2] = 'b'
+	assert.Equal(t, `{"a":1}`, r.String())
+	v, err := r.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a":1}`, v)
+	assert.Error(t, r.Scan([]byte(`{a:1}`)))
+	assert.NoError(t, r.Scan("null"))
+	assert.True(t, r.Valid)
+	assert.NoError(t, r.Scan(nil))
+	assert.False(t, r.Valid)
+	v, err = r.Value()
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+	b, err := json.Marshal(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "null", string(b))
+}
@@ -0,0 +1,333 @@
+package sqltypes
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// MergePatch applies an RFC 7386 merge patch: objects are merged
+// recursively, null removes a member and anything else replaces the
+// target. A NULL r is patched as the JSON null.
+func (r NullRawJSON) MergePatch(patch []byte) (NullRawJSON, error) {
+	p, err := decodeJSON(patch)
+	if err != nil {
+		return NullRawJSON{}, err
+	}
+	var doc interface{}
+	if r.Valid {
+		if doc, err = decodeJSON(r.JSON); err != nil {
+			return NullRawJSON{}, err
+		}
+	}
+	return rawJSON(mergePatch(doc, p))
+}
+
+func mergePatch(target, patch interface{}) interface{} {
+	pm, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	tm, ok := target.(map[string]interface{})
+	if !ok {
+		tm = make(map[string]interface{})
+	}
+	for k, v := range pm {
+		if v == nil {
+			delete(tm, k)
+		} else {
+			tm[k] = mergePatch(tm[k], v)
+		}
+	}
+	return tm
+}
+
+func rawJSON(v interface{}) (NullRawJSON, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return NullRawJSON{}, err
+	}
+	return NullRawJSON{JSON: b, Valid: true}, nil
+}
+
+type jsonPatchOp struct {
+	Op    string          `json:"op"`
+	Path  *string         `json:"path"`
+	From  *string         `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// Patch applies an RFC 6902 JSON patch (add, remove, replace, move, copy
+// and test operations). The patch is atomic: if an operation fails the
+// error is returned and r is left as is.
+func (r NullRawJSON) Patch(patch []byte) (NullRawJSON, error) {
+	var ops []jsonPatchOp
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return NullRawJSON{}, fmt.Errorf("invalid JSON patch: %v", err)
+	}
+	var doc interface{}
+	if r.Valid {
+		var err error
+		if doc, err = decodeJSON(r.JSON); err != nil {
+			return NullRawJSON{}, err
+		}
+	}
+	for i, op := range ops {
+		var err error
+		if doc, err = applyPatchOp(doc, op); err != nil {
+			return NullRawJSON{}, fmt.Errorf("JSON patch operation %d (%s): %v", i, op.Op, err)
+		}
+	}
+	return rawJSON(doc)
+}
+
+func applyPatchOp(doc interface{}, op jsonPatchOp) (interface{}, error) {
+	if op.Path == nil {
+		return nil, fmt.Errorf("missing path")
+	}
+	path, err := parsePointer(*op.Path)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	switch op.Op {
+	case "add", "replace", "test":
+		if len(op.Value) == 0 {
+			return nil, fmt.Errorf("missing value")
+		}
+		if value, err = decodeJSON(op.Value); err != nil {
+			return nil, err
+		}
+	case "move", "copy":
+		if op.From == nil {
+			return nil, fmt.Errorf("missing from")
+		}
+		from, err := parsePointer(*op.From)
+		if err != nil {
+			return nil, err
+		}
+		v, ok := pointerGet(doc, from)
+		if !ok {
+			return nil, fmt.Errorf("path '%s' not found", *op.From)
+		}
+		if op.Op == "copy" {
+			return pointerAdd(doc, path, copyJSON(v))
+		}
+		if len(from) < len(path) && strings.HasPrefix(*op.Path, *op.From+"/") {
+			return nil, fmt.Errorf("cannot move '%s' into itself", *op.From)
+		}
+		if doc, err = pointerRemove(doc, from); err != nil {
+			return nil, err
+		}
+		return pointerAdd(doc, path, v)
+	case "remove":
+	default:
+		return nil, fmt.Errorf("unknown operation")
+	}
+	switch op.Op {
+	case "add":
+		return pointerAdd(doc, path, value)
+	case "remove":
+		return pointerRemove(doc, path)
+	case "replace":
+		if _, ok := pointerGet(doc, path); !ok {
+			return nil, fmt.Errorf("path '%s' not found", *op.Path)
+		}
+		if doc, err = pointerRemove(doc, path); err != nil {
+			return nil, err
+		}
+		return pointerAdd(doc, path, value)
+	}
+	// test
+	v, ok := pointerGet(doc, path)
+	if !ok || !equalJSON(v, value) {
+		return nil, fmt.Errorf("test failed at '%s'", *op.Path)
+	}
+	return doc, nil
+}
+
+// parsePointer splits an RFC 6901 JSON pointer ("/a/0/b~1c") into tokens
+func parsePointer(p string) ([]string, error) {
+	if p == "" {
+		return nil, nil
+	}
+	if p[0] != '/' {
+		return nil, fmt.Errorf("invalid JSON pointer '%s'", p)
+	}
+	tokens := strings.Split(p[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.Replace(strings.Replace(t, "~1", "/", -1), "~0", "~", -1)
+	}
+	return tokens, nil
+}
+
+// arrayIndex parses an array index token; "-" is n (past the end) when
+// allowed
+func arrayIndex(t string, n int, dash bool) (int, bool) {
+	if t == "-" && dash {
+		return n, true
+	}
+	if t == "" || (len(t) > 1 && t[0] == '0') {
+		return 0, false
+	}
+	i, err := strconv.Atoi(t)
+	if err != nil || i < 0 {
+		return 0, false
+	}
+	return i, true
+}
+
+func pointerGet(doc interface{}, path []string) (interface{}, bool) {
+	v := doc
+	for _, t := range path {
+		switch c := v.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if v, ok = c[t]; !ok {
+				return nil, false
+			}
+		case []interface{}:
+			i, ok := arrayIndex(t, len(c), false)
+			if !ok || i >= len(c) {
+				return nil, false
+			}
+			v = c[i]
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// pointerUpdate calls f with the container of the last token of path and
+// returns the document with the container f returns
+func pointerUpdate(doc interface{}, path []string, f func(c interface{}, t string) (interface{}, error)) (interface{}, error) {
+	if len(path) == 1 {
+		return f(doc, path[0])
+	}
+	child, ok := pointerGet(doc, path[:1])
+	if !ok {
+		return nil, fmt.Errorf("path '/%s' not found", path[0])
+	}
+	nc, err := pointerUpdate(child, path[1:], f)
+	if err != nil {
+		return nil, err
+	}
+	switch c := doc.(type) {
+	case map[string]interface{}:
+		c[path[0]] = nc
+	case []interface{}:
+		i, _ := arrayIndex(path[0], len(c), false)
+		c[i] = nc
+	}
+	return doc, nil
+}
+
+func pointerAdd(doc interface{}, path []string, v interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return v, nil
+	}
+	return pointerUpdate(doc, path, func(c interface{}, t string) (interface{}, error) {
+		switch c := c.(type) {
+		case map[string]interface{}:
+			c[t] = v
+			return c, nil
+		case []interface{}:
+			i, ok := arrayIndex(t, len(c), true)
+			if !ok || i > len(c) {
+				return nil, fmt.Errorf("invalid array index '%s'", t)
+			}
+			c = append(c, nil)
+			copy(c[i+1:], c[i:])
+			c[i] = v
+			return c, nil
+		}
+		return nil, fmt.Errorf("cannot add '%s' to a scalar", t)
+	})
+}
+
+func pointerRemove(doc interface{}, path []string) (interface{}, error) {
+	if len(path) == 0 {
+		return nil, nil
+	}
+	return pointerUpdate(doc, path, func(c interface{}, t string) (interface{}, error) {
+		switch c := c.(type) {
+		case map[string]interface{}:
+			if _, ok := c[t]; !ok {
+				return nil, fmt.Errorf("member '%s' not found", t)
+			}
+			delete(c, t)
+			return c, nil
+		case []interface{}:
+			i, ok := arrayIndex(t, len(c), false)
+			if !ok || i >= len(c) {
+				return nil, fmt.Errorf("invalid array index '%s'", t)
+			}
+			return append(c[:i:i], c[i+1:]...), nil
+		}
+		return nil, fmt.Errorf("cannot remove '%s' from a scalar", t)
+	})
+}
+
+func copyJSON(v interface{}) interface{} {
+	switch c := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(c))
+		for k, e := range c {
+			m[k] = copyJSON(e)
+		}
+		return m
+	case []interface{}:
+		a := make([]interface{}, len(c))
+		for i, e := range c {
+			a[i] = copyJSON(e)
+		}
+		return a
+	}
+	return v
+}
+
+// equalJSON compares two decoded documents; numbers are compared by value
+// (1 equals 1.0)
+func equalJSON(a, b interface{}) bool {
+	switch x := a.(type) {
+	case map[string]interface{}:
+		y, ok := b.(map[string]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for k, v := range x {
+			w, ok := y[k]
+			if !ok || !equalJSON(v, w) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		y, ok := b.([]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !equalJSON(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		y, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		dx, err1 := decimal.NewFromString(string(x))
+		dy, err2 := decimal.NewFromString(string(y))
+		if err1 != nil || err2 != nil {
+			return x == y
+		}
+		return dx.Equal(dy)
+	}
+	return a == b
+}
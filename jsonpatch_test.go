@@ -0,0 +1,93 @@
+package sqltypes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergePatch(t *testing.T) {
+	// RFC 7386 section 3
+	r := rawDoc(t, `{"title":"Goodbye!","author":{"givenName":"John","familyName":"Doe"},"tags":["example","sample"],"content":"This will be unchanged"}`)
+	p, err := r.MergePatch([]byte(`{"title":"Hello!","phoneNumber":"+01-123-456-7890","author":{"familyName":null},"tags":["example"]}`))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"title":"Hello!","author":{"givenName":"John"},"tags":["example"],"content":"This will be unchanged","phoneNumber":"+01-123-456-7890"}`, p.String())
+	// r is unchanged
+	assert.Contains(t, r.String(), "Goodbye!")
+	//
+	tests := []struct{ doc, patch, out string }{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+		{`{"n":1.10}`, `{}`, `{"n":1.10}`},
+	}
+	for _, tt := range tests {
+		p, err := rawDoc(t, tt.doc).MergePatch([]byte(tt.patch))
+		assert.NoError(t, err)
+		assert.JSONEq(t, tt.out, p.String(), tt.doc+" "+tt.patch)
+	}
+	p, err = NullRawJSON{}.MergePatch([]byte(`{"a":1}`))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a":1}`, p.String())
+	_, err = r.MergePatch([]byte(`{`))
+	assert.Error(t, err)
+}
+
+func TestJSONPatch(t *testing.T) {
+	// RFC 6902 appendix A
+	tests := []struct{ doc, patch, out string }{
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux"}]`, `{"baz":"qux","foo":"bar"}`},
+		{`{"foo":["bar","baz"]}`, `[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}`},
+		{`{"baz":"qux","foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, `{"foo":"bar"}`},
+		{`{"foo":["bar","qux","baz"]}`, `[{"op":"remove","path":"/foo/1"}]`, `{"foo":["bar","baz"]}`},
+		{`{"baz":"qux","foo":"bar"}`, `[{"op":"replace","path":"/baz","value":"boo"}]`, `{"baz":"boo","foo":"bar"}`},
+		{`{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`, `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`, `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`},
+		{`{"foo":["all","grass","cows","eat"]}`, `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`, `{"foo":["all","cows","eat","grass"]}`},
+		{`{"baz":"qux","foo":["a",2,"c"]}`, `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2.0}]`, `{"baz":"qux","foo":["a",2,"c"]}`},
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/child","value":{"grandchild":{}}}]`, `{"foo":"bar","child":{"grandchild":{}}}`},
+		{`{"foo":["bar"]}`, `[{"op":"add","path":"/foo/-","value":["abc","def"]}]`, `{"foo":["bar",["abc","def"]]}`},
+		{`{"foo":null}`, `[{"op":"test","path":"/foo","value":null}]`, `{"foo":null}`},
+		{`{"/":9,"~1":10}`, `[{"op":"test","path":"/~01","value":10},{"op":"remove","path":"/~1"}]`, `{"~1":10}`},
+		{`{"a":{"b":[1]}}`, `[{"op":"copy","from":"/a/b","path":"/c"},{"op":"add","path":"/c/-","value":2}]`, `{"a":{"b":[1]},"c":[1,2]}`},
+		{`{"a":1}`, `[{"op":"replace","path":"","value":[1]}]`, `[1]`},
+	}
+	for _, tt := range tests {
+		p, err := rawDoc(t, tt.doc).Patch([]byte(tt.patch))
+		if assert.NoError(t, err, tt.patch) {
+			assert.JSONEq(t, tt.out, p.String(), tt.patch)
+		}
+	}
+}
+
+func TestJSONPatchErrors(t *testing.T) {
+	tests := []struct{ doc, patch string }{
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz/bat","value":"qux"}]`},
+		{`{"baz":"qux"}`, `[{"op":"test","path":"/baz","value":"bar"}]`},
+		{`{"foo":["bar"]}`, `[{"op":"add","path":"/foo/2","value":1}]`},
+		{`{"foo":["bar"]}`, `[{"op":"add","path":"/foo/01","value":1}]`},
+		{`{"foo":1}`, `[{"op":"remove","path":"/bar"}]`},
+		{`{"foo":1}`, `[{"op":"replace","path":"/bar","value":2}]`},
+		{`{"foo":1}`, `[{"op":"add","path":"/bar"}]`},
+		{`{"foo":1}`, `[{"op":"move","from":"/bar","path":"/baz"}]`},
+		{`{"foo":{"a":1}}`, `[{"op":"move","from":"/foo","path":"/foo/a/b"}]`},
+		{`{"foo":1}`, `[{"op":"nope","path":"/foo"}]`},
+		{`{"foo":1}`, `[{"op":"add","path":"foo","value":1}]`},
+		{`{"foo":1}`, `{"op":"add"}`},
+	}
+	for _, tt := range tests {
+		_, err := rawDoc(t, tt.doc).Patch([]byte(tt.patch))
+		assert.Error(t, err, tt.patch)
+	}
+	// a failing operation leaves the value untouched
+	r := rawDoc(t, `{"a":[1,2]}`)
+	_, err := r.Patch([]byte(`[{"op":"remove","path":"/a/0"},{"op":"test","path":"/a/0","value":1}]`))
+	assert.Error(t, err)
+	assert.Equal(t, `{"a":[1,2]}`, r.String())
+}
@@ -0,0 +1,156 @@
+package sqltypes
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// decodeJSON decodes b keeping numbers as json.Number
+func decodeJSON(b []byte) (interface{}, error) {
+	if !json.Valid(b) {
+		return nil, fmt.Errorf("invalid JSON '%s'", string(b))
+	}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// parseJSONPath parses a path like $.a.b[0] or $['a b'][1] into keys
+// (string) and indexes (int)
+func parseJSONPath(path string) ([]interface{}, error) {
+	invalid := fmt.Errorf("invalid JSON path '%s'", path)
+	p := strings.TrimSpace(path)
+	if !strings.HasPrefix(p, "$") {
+		return nil, invalid
+	}
+	p = p[1:]
+	var steps []interface{}
+	for len(p) > 0 {
+		switch p[0] {
+		case '.':
+			i := 1
+			for i < len(p) && p[i] != '.' && p[i] != '[' {
+				i++
+			}
+			if i == 1 {
+				return nil, invalid
+			}
+			steps = append(steps, p[1:i])
+			p = p[i:]
+		case '[':
+			if len(p) > 1 && (p[1] == '\'' || p[1] == '"') {
+				// quoted key, which may contain '.' and ']'
+				q := p[1]
+				j := 2
+				for j < len(p) && !(p[j] == q && p[j-1] != '\\') {
+					j++
+				}
+				if j+1 >= len(p) || p[j+1] != ']' {
+					return nil, invalid
+				}
+				steps = append(steps, strings.Replace(p[2:j], `\`+string(q), string(q), -1))
+				p = p[j+2:]
+				continue
+			}
+			end := strings.IndexByte(p, ']')
+			if end < 0 {
+				return nil, invalid
+			}
+			n, err := strconv.Atoi(p[1:end])
+			if err != nil || n < 0 {
+				return nil, invalid
+			}
+			steps = append(steps, n)
+			p = p[end+1:]
+		default:
+			return nil, invalid
+		}
+	}
+	return steps, nil
+}
+
+// lookupJSON follows steps from v; ok is false if the path doesn't exist
+func lookupJSON(v interface{}, steps []interface{}) (interface{}, bool) {
+	for _, s := range steps {
+		switch k := s.(type) {
+		case string:
+			m, ok := v.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			if v, ok = m[k]; !ok {
+				return nil, false
+			}
+		case int:
+			a, ok := v.([]interface{})
+			if !ok || k >= len(a) {
+				return nil, false
+			}
+			v = a[k]
+		}
+	}
+	return v, true
+}
+
+func (r NullRawJSON) lookup(path string) (interface{}, bool, error) {
+	steps, err := parseJSONPath(path)
+	if err != nil {
+		return nil, false, err
+	}
+	if !r.Valid {
+		return nil, false, nil
+	}
+	doc, err := decodeJSON(r.JSON)
+	if err != nil {
+		return nil, false, err
+	}
+	v, ok := lookupJSON(doc, steps)
+	return v, ok, nil
+}
+
+// Path returns the value at path ($.a.b[0], $['a key']) as a document. It
+// is NULL if the path doesn't exist.
+func (r NullRawJSON) Path(path string) (NullRawJSON, error) {
+	v, ok, err := r.lookup(path)
+	if err != nil || !ok {
+		return NullRawJSON{}, err
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return NullRawJSON{}, err
+	}
+	return NullRawJSON{JSON: b, Valid: true}, nil
+}
+
+// Extract scans the value at path into dest (a NullString, NullDecimal,
+// NullBool...) as a driver would: strings and numbers as strings, booleans
+// as bool, objects and arrays as JSON bytes. A missing path or a JSON null
+// scans NULL.
+func (r NullRawJSON) Extract(path string, dest sql.Scanner) error {
+	v, ok, err := r.lookup(path)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return dest.Scan(nil)
+	}
+	switch x := v.(type) {
+	case nil, string, bool:
+		return dest.Scan(x)
+	case json.Number:
+		return dest.Scan(string(x))
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return dest.Scan(b)
+}
@@ -0,0 +1,69 @@
+package sqltypes
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func rawDoc(t *testing.T, s string) NullRawJSON {
+	var r NullRawJSON
+	assert.NoError(t, r.Scan([]byte(s)))
+	return r
+}
+
+func TestParseJSONPath(t *testing.T) {
+	steps, err := parseJSONPath(`$.a.b[0]['c.d']["e]"][12]`)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"a", "b", 0, "c.d", "e]", 12}, steps)
+	steps, err = parseJSONPath("$")
+	assert.NoError(t, err)
+	assert.Empty(t, steps)
+	for _, p := range []string{"", "a.b", "$.", "$..a", "$[", "$[x]", "$[-1]", "$['a]", "$a"} {
+		_, err = parseJSONPath(p)
+		assert.Error(t, err, p)
+	}
+}
+
+func TestNullRawJSONExtract(t *testing.T) {
+	r := rawDoc(t, `{"user":{"name":"Ana","active":"Y","admin":true,"score":12.50,"tags":["a","b"],"none":null},"items":[{"price":"10.99"}]}`)
+	var s NullString
+	assert.NoError(t, r.Extract("$.user.name", &s))
+	assert.Equal(t, NullString("Ana"), s)
+	assert.NoError(t, r.Extract("$.user.tags[1]", &s))
+	assert.Equal(t, NullString("b"), s)
+	assert.NoError(t, r.Extract("$.user.missing", &s))
+	assert.Equal(t, NullString(""), s)
+	//
+	var b NullBool
+	assert.NoError(t, r.Extract("$.user.admin", &b))
+	assert.True(t, bool(b))
+	assert.NoError(t, r.Extract("$['user']['active']", &b))
+	assert.True(t, bool(b))
+	//
+	var d NullDecimalOpt
+	assert.NoError(t, r.Extract("$.user.score", &d))
+	assert.True(t, d.Decimal.Equal(decimal.New(125, -1)))
+	assert.NoError(t, r.Extract("$.items[0].price", &d))
+	assert.Equal(t, "10.99", d.String())
+	assert.NoError(t, r.Extract("$.user.none", &d))
+	assert.False(t, d.Valid)
+	assert.NoError(t, r.Extract("$.items[5].price", &d))
+	assert.False(t, d.Valid)
+	//
+	var j NullJSON[[]string]
+	assert.NoError(t, r.Extract("$.user.tags", &j))
+	assert.Equal(t, []string{"a", "b"}, j.V)
+	//
+	p, err := r.Path("$.items[0]")
+	assert.NoError(t, err)
+	assert.Equal(t, `{"price":"10.99"}`, p.String())
+	p, err = r.Path("$.nope")
+	assert.NoError(t, err)
+	assert.False(t, p.Valid)
+	assert.Error(t, r.Extract("user.name", &s))
+	//
+	assert.NoError(t, NullRawJSON{}.Extract("$.a", &d))
+	assert.False(t, d.Valid)
+}
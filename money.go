@@ -0,0 +1,392 @@
+package sqltypes
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// ISO 4217 currencies by number of minor unit digits
+const iso4217 = `
+0 BIF CLP DJF GNF ISK JPY KMF KRW PYG RWF UGX UYI VND VUV XAF XOF XPF
+2 AED AFN ALL AMD ANG AOA ARS AUD AWG AZN BAM BBD BDT BGN BMD BND BOB BOV BRL
+2 BSD BTN BWP BYN BZD CAD CDF CHE CHF CHW CNY COP COU CRC CUC CUP CVE CZK DKK
+2 DOP DZD EGP ERN ETB EUR FJD FKP GBP GEL GHS GIP GMD GTQ GYD HKD HNL HTG HUF
+2 IDR ILS INR IRR JMD KES KGS KHR KPW KYD KZT LAK LBP LKR LRD LSL MAD MDL MGA
+2 MKD MMK MNT MOP MRU MUR MVR MWK MXN MXV MYR MZN NAD NGN NIO NOK NPR NZD PAB
+2 PEN PGK PHP PKR PLN QAR RON RSD RUB SAR SBD SCR SDG SEK SGD SHP SLE SLL SOS
+2 SRD SSP STN SVC SYP SZL THB TJS TMT TOP TRY TTD TWD TZS UAH USD USN UYU UZS
+2 VED VES WST XCD XCG YER ZAR ZMW ZWG ZWL
+3 BHD IQD JOD KWD LYD OMR TND
+4 CLF UYW
+`
+
+var currencyDigits = make(map[string]int)
+
+func init() {
+	for _, line := range strings.Split(strings.TrimSpace(iso4217), "\n") {
+		f := strings.Fields(line)
+		for _, code := range f[1:] {
+			currencyDigits[code] = int(f[0][0] - '0')
+		}
+	}
+}
+
+// CurrencyDigits returns the number of minor unit digits of an ISO 4217
+// currency code (2 for "BRL", 0 for "JPY")
+func CurrencyDigits(code string) (int, bool) {
+	d, ok := currencyDigits[code]
+	return d, ok
+}
+
+// NullMoney is an amount of a currency (ISO 4217 code). Arithmetic between
+// different currencies is an error. It is stored either in a composite
+// column ("(10.00,BRL)", see Scan and Value) or in two columns (see
+// ScanColumns and ColumnValues). Valid = false is NULL.
+type NullMoney struct {
+	Amount   decimal.Decimal
+	Currency string
+	Valid    bool
+
+	// hasAmount is set by ScanColumns when the amount isn't NULL
+	hasAmount bool
+}
+
+// Money returns a valid NullMoney; currency must be an ISO 4217 code
+func Money(amount decimal.Decimal, currency string) (NullMoney, error) {
+	if _, ok := currencyDigits[currency]; !ok {
+		return NullMoney{}, fmt.Errorf("invalid currency '%s'", currency)
+	}
+	return NullMoney{Amount: amount, Currency: currency, Valid: true}, nil
+}
+
+// Digits returns the number of minor unit digits of the currency
+func (m NullMoney) Digits() int {
+	d, ok := currencyDigits[m.Currency]
+	if !ok {
+		return 2
+	}
+	return d
+}
+
+func (m NullMoney) sameCurrency(o NullMoney) error {
+	if m.Currency != o.Currency {
+		return fmt.Errorf("currency mismatch: %s and %s", m.Currency, o.Currency)
+	}
+	return nil
+}
+
+// Add returns m + o. It is NULL if either is NULL.
+func (m NullMoney) Add(o NullMoney) (NullMoney, error) {
+	if !m.Valid || !o.Valid {
+		return NullMoney{}, nil
+	}
+	if err := m.sameCurrency(o); err != nil {
+		return NullMoney{}, err
+	}
+	m.Amount = m.Amount.Add(o.Amount)
+	return m, nil
+}
+
+// Sub returns m - o. It is NULL if either is NULL.
+func (m NullMoney) Sub(o NullMoney) (NullMoney, error) {
+	if !m.Valid || !o.Valid {
+		return NullMoney{}, nil
+	}
+	if err := m.sameCurrency(o); err != nil {
+		return NullMoney{}, err
+	}
+	m.Amount = m.Amount.Sub(o.Amount)
+	return m, nil
+}
+
+// Mul returns m * f; the result isn't rounded (see Round)
+func (m NullMoney) Mul(f decimal.Decimal) NullMoney {
+	if m.Valid {
+		m.Amount = m.Amount.Mul(f)
+	}
+	return m
+}
+
+// Neg returns -m
+func (m NullMoney) Neg() NullMoney {
+	if m.Valid {
+		m.Amount = m.Amount.Neg()
+	}
+	return m
+}
+
+// Round rounds m to the minor unit of its currency
+func (m NullMoney) Round(mode RoundingMode) NullMoney {
+	if m.Valid {
+		r, err := roundDecimal(m.Amount, m.Digits(), mode)
+		if err == nil {
+			m.Amount = r
+		}
+	}
+	return m
+}
+
+// Cmp compares m and o (-1, 0 or 1). NULL is less than any amount.
+func (m NullMoney) Cmp(o NullMoney) (int, error) {
+	switch {
+	case !m.Valid && !o.Valid:
+		return 0, nil
+	case !m.Valid:
+		return -1, nil
+	case !o.Valid:
+		return 1, nil
+	}
+	if err := m.sameCurrency(o); err != nil {
+		return 0, err
+	}
+	return m.Amount.Cmp(o.Amount), nil
+}
+
+// IsZero reports whether m is a zero amount
+func (m NullMoney) IsZero() bool {
+	return m.Valid && m.Amount.Sign() == 0
+}
+
+// Allocate splits m by ratios without losing minor units: the remainder is
+// given, one unit at a time, to the first parts. Allocating 0.05 by 1:1
+// gives 0.03 and 0.02. m must not have fractions of a minor unit.
+func (m NullMoney) Allocate(ratios ...int) ([]NullMoney, error) {
+	if len(ratios) == 0 {
+		return nil, fmt.Errorf("no ratios to allocate")
+	}
+	var sum int64
+	for _, r := range ratios {
+		if r < 0 {
+			return nil, fmt.Errorf("invalid ratio %d", r)
+		}
+		sum += int64(r)
+	}
+	if sum == 0 {
+		return nil, fmt.Errorf("ratios sum to zero")
+	}
+	parts := make([]NullMoney, len(ratios))
+	if !m.Valid {
+		return parts, nil
+	}
+	digits := m.Digits()
+	units, ok := minorUnits(m.Amount, digits)
+	if !ok {
+		return nil, fmt.Errorf("cannot allocate %s: fractions of a minor unit", m.String())
+	}
+	shares := make([]*big.Int, len(ratios))
+	rest := new(big.Int).Set(units)
+	for i, r := range ratios {
+		shares[i] = new(big.Int).Mul(units, big.NewInt(int64(r)))
+		shares[i].Quo(shares[i], big.NewInt(sum))
+		rest.Sub(rest, shares[i])
+	}
+	one := big.NewInt(int64(units.Sign()))
+	for i := 0; rest.Sign() != 0; i = (i + 1) % len(shares) {
+		if ratios[i] == 0 {
+			continue
+		}
+		shares[i].Add(shares[i], one)
+		rest.Sub(rest, one)
+	}
+	for i, s := range shares {
+		parts[i] = NullMoney{Amount: decimal.NewFromBigInt(s, int32(-digits)), Currency: m.Currency, Valid: true}
+	}
+	return parts, nil
+}
+
+// Split splits m in n parts (see Allocate)
+func (m NullMoney) Split(n int) ([]NullMoney, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("invalid number of parts %d", n)
+	}
+	ratios := make([]int, n)
+	for i := range ratios {
+		ratios[i] = 1
+	}
+	return m.Allocate(ratios...)
+}
+
+// minorUnits returns d in minor units; ok is false if it isn't whole
+func minorUnits(d decimal.Decimal, digits int) (*big.Int, bool) {
+	s := d.Shift(int32(digits))
+	t := s.Truncate(0)
+	if !t.Equal(s) {
+		return nil, false
+	}
+	b, ok := new(big.Int).SetString(t.String(), 10)
+	return b, ok
+}
+
+// amountString returns the amount with at least the minor unit digits
+func (m NullMoney) amountString() string {
+	d := int32(m.Digits())
+	if m.Amount.Exponent() >= -d {
+		return m.Amount.StringFixed(d)
+	}
+	return m.Amount.String()
+}
+
+// String returns "10.00 BRL" or "" if NULL
+func (m NullMoney) String() string {
+	if !m.Valid {
+		return ""
+	}
+	return m.amountString() + " " + m.Currency
+}
+
+// Scan implements the Scanner interface. It reads a composite column:
+// "(10.00,BRL)", "10.00 BRL" or "BRL 10.00".
+func (m *NullMoney) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*m = NullMoney{}
+		return nil
+	case []byte:
+		return m.parse(string(v))
+	case string:
+		return m.parse(v)
+	}
+	return fmt.Errorf("unsupported Scan, storing driver.Value type %T into type %T", value, m)
+}
+
+func (m *NullMoney) parse(s string) error {
+	t := strings.TrimSpace(s)
+	var amount, code string
+	if strings.HasPrefix(t, "(") && strings.HasSuffix(t, ")") {
+		f := strings.Split(t[1:len(t)-1], ",")
+		if len(f) != 2 {
+			return fmt.Errorf("invalid money '%s'", s)
+		}
+		amount, code = strings.Trim(f[0], `" `), strings.Trim(f[1], `" `)
+		if amount == "" && code == "" {
+			*m = NullMoney{}
+			return nil
+		}
+	} else {
+		f := strings.Fields(t)
+		if len(f) != 2 {
+			return fmt.Errorf("invalid money '%s'", s)
+		}
+		amount, code = f[0], f[1]
+		if _, ok := currencyDigits[amount]; ok {
+			amount, code = code, amount
+		}
+	}
+	d, err := decimal.NewFromString(amount)
+	if err != nil {
+		return fmt.Errorf("invalid money '%s'", s)
+	}
+	nm, err := Money(d, code)
+	if err != nil {
+		return err
+	}
+	*m = nm
+	return nil
+}
+
+// Value implements the driver Valuer interface. It writes a composite
+// value: "(10.00,BRL)".
+func (m NullMoney) Value() (driver.Value, error) {
+	if !m.Valid {
+		return nil, nil
+	}
+	return "(" + m.amountString() + "," + m.Currency + ")", nil
+}
+
+// ScanColumns returns the destinations of the amount and currency columns
+// when they are stored apart:
+//
+//	a, c := m.ScanColumns()
+//	row.Scan(&id, a, c)
+//
+// m is NULL if either column is NULL.
+func (m *NullMoney) ScanColumns() (amount, currency sql.Scanner) {
+	return moneyAmount{m}, moneyCurrency{m}
+}
+
+// ColumnValues returns the values of the amount and currency columns when
+// they are stored apart
+func (m NullMoney) ColumnValues() (amount, currency driver.Valuer) {
+	if !m.Valid {
+		return NullDecimalOpt{}, NullString("")
+	}
+	return NullDecimalOpt{Decimal: m.Amount, Valid: true}, NullString(m.Currency)
+}
+
+type moneyAmount struct{ m *NullMoney }
+
+func (a moneyAmount) Scan(value interface{}) error {
+	var d NullDecimalOpt
+	if err := d.Scan(value); err != nil {
+		return err
+	}
+	a.m.Amount, a.m.hasAmount = d.D(), d.Valid
+	a.m.Valid = d.Valid && a.m.Currency != ""
+	return nil
+}
+
+type moneyCurrency struct{ m *NullMoney }
+
+func (c moneyCurrency) Scan(value interface{}) error {
+	var s NullString
+	if err := s.Scan(value); err != nil {
+		return err
+	}
+	code := strings.TrimSpace(string(s))
+	if code == "" {
+		c.m.Currency, c.m.Valid = "", false
+		return nil
+	}
+	if _, ok := currencyDigits[code]; !ok {
+		return fmt.Errorf("invalid currency '%s'", code)
+	}
+	c.m.Currency, c.m.Valid = code, c.m.hasAmount
+	return nil
+}
+
+type moneyJSON struct {
+	Amount   json.RawMessage `json:"amount"`
+	Currency string          `json:"currency"`
+}
+
+// MarshalJSON implements json.Marshaler
+func (m NullMoney) MarshalJSON() ([]byte, error) {
+	if !m.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(moneyJSON{
+		Amount:   json.RawMessage(`"` + m.amountString() + `"`),
+		Currency: m.Currency,
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (m *NullMoney) UnmarshalJSON(v []byte) error {
+	if len(v) == 0 || string(v) == "null" {
+		*m = NullMoney{}
+		return nil
+	}
+	var mj moneyJSON
+	if err := json.Unmarshal(v, &mj); err != nil {
+		return err
+	}
+	var d NullDecimalOpt
+	if err := d.UnmarshalJSON(mj.Amount); err != nil {
+		return err
+	}
+	if !d.Valid {
+		return fmt.Errorf("invalid money %s: missing amount", string(v))
+	}
+	nm, err := Money(d.Decimal, mj.Currency)
+	if err != nil {
+		return err
+	}
+	*m = nm
+	return nil
+}
@@ -0,0 +1,159 @@
+package sqltypes
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func mustMoney(t *testing.T, amount, currency string) NullMoney {
+	m, err := Money(decimal.RequireFromString(amount), currency)
+	assert.NoError(t, err)
+	return m
+}
+
+func TestCurrencyDigits(t *testing.T) {
+	for code, digits := range map[string]int{"BRL": 2, "USD": 2, "JPY": 0, "KWD": 3, "CLF": 4} {
+		d, ok := CurrencyDigits(code)
+		assert.True(t, ok, code)
+		assert.Equal(t, digits, d, code)
+	}
+	_, ok := CurrencyDigits("XXX")
+	assert.False(t, ok)
+	_, err := Money(decimal.Zero, "brl")
+	assert.Error(t, err)
+}
+
+func TestNullMoneyArithmetic(t *testing.T) {
+	a := mustMoney(t, "10.50", "BRL")
+	b := mustMoney(t, "0.75", "BRL")
+	c, err := a.Add(b)
+	assert.NoError(t, err)
+	assert.Equal(t, "11.25 BRL", c.String())
+	c, err = a.Sub(b)
+	assert.NoError(t, err)
+	assert.Equal(t, "9.75 BRL", c.String())
+	_, err = a.Add(mustMoney(t, "1", "USD"))
+	assert.Error(t, err)
+	_, err = a.Cmp(mustMoney(t, "1", "USD"))
+	assert.Error(t, err)
+	n, err := a.Cmp(b)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	c, err = a.Add(NullMoney{})
+	assert.NoError(t, err)
+	assert.False(t, c.Valid)
+	//
+	assert.Equal(t, "3.465 BRL", a.Mul(decimal.RequireFromString("0.33")).String())
+	assert.Equal(t, "3.47 BRL", a.Mul(decimal.RequireFromString("0.33")).Round(RoundHalfUp).String())
+	assert.Equal(t, "-10.50 BRL", a.Neg().String())
+	assert.Equal(t, "1000 JPY", mustMoney(t, "1000", "JPY").String())
+}
+
+func TestNullMoneyAllocate(t *testing.T) {
+	parts, err := mustMoney(t, "0.05", "BRL").Split(2)
+	assert.NoError(t, err)
+	assert.Equal(t, "0.03 BRL", parts[0].String())
+	assert.Equal(t, "0.02 BRL", parts[1].String())
+	//
+	parts, err = mustMoney(t, "100", "USD").Split(3)
+	assert.NoError(t, err)
+	assert.Equal(t, "33.34 USD", parts[0].String())
+	assert.Equal(t, "33.33 USD", parts[1].String())
+	assert.Equal(t, "33.33 USD", parts[2].String())
+	//
+	parts, err = mustMoney(t, "-10", "BRL").Allocate(70, 0, 30)
+	assert.NoError(t, err)
+	assert.Equal(t, "-7.00 BRL", parts[0].String())
+	assert.Equal(t, "0.00 BRL", parts[1].String())
+	assert.Equal(t, "-3.00 BRL", parts[2].String())
+	//
+	parts, err = mustMoney(t, "1001", "JPY").Allocate(1, 1, 1)
+	assert.NoError(t, err)
+	sum := NullMoney{Currency: "JPY", Valid: true}
+	for _, p := range parts {
+		sum, _ = sum.Add(p)
+	}
+	assert.Equal(t, "1001 JPY", sum.String())
+	assert.Equal(t, "334 JPY", parts[0].String())
+	//
+	_, err = mustMoney(t, "0.005", "BRL").Split(2)
+	assert.Error(t, err)
+	_, err = mustMoney(t, "1", "BRL").Allocate(0, 0)
+	assert.Error(t, err)
+	_, err = mustMoney(t, "1", "BRL").Split(0)
+	assert.Error(t, err)
+}
+
+func TestNullMoneyScanValue(t *testing.T) {
+	var m NullMoney
+	for _, s := range []string{"(10.00,BRL)", `("10.00","BRL")`, "10.00 BRL", "BRL 10"} {
+		assert.NoError(t, m.Scan([]byte(s)), s)
+		assert.Equal(t, "10.00 BRL", m.String(), s)
+	}
+	v, err := m.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "(10.00,BRL)", v)
+	assert.NoError(t, m.Scan(nil))
+	assert.False(t, m.Valid)
+	v, err = m.Value()
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+	assert.NoError(t, m.Scan("(,)"))
+	assert.False(t, m.Valid)
+	assert.Error(t, m.Scan("(10.00,XYZ)"))
+	assert.Error(t, m.Scan("10.00"))
+	assert.Error(t, m.Scan(int64(10)))
+}
+
+func TestNullMoneyColumns(t *testing.T) {
+	var m NullMoney
+	a, c := m.ScanColumns()
+	assert.NoError(t, a.Scan([]byte("12.3")))
+	assert.NoError(t, c.Scan([]byte("USD")))
+	assert.True(t, m.Valid)
+	assert.Equal(t, "12.30 USD", m.String())
+	// currency before amount, NULL amount
+	assert.NoError(t, c.Scan("BRL"))
+	assert.NoError(t, a.Scan(nil))
+	assert.False(t, m.Valid)
+	assert.NoError(t, a.Scan(int64(5)))
+	assert.True(t, m.Valid)
+	assert.NoError(t, c.Scan(nil))
+	assert.False(t, m.Valid)
+	assert.Error(t, c.Scan("ZZZ"))
+	//
+	av, cv := mustMoney(t, "1.5", "EUR").ColumnValues()
+	v, err := av.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "1.5", v)
+	v, err = cv.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "EUR", v)
+	av, cv = NullMoney{}.ColumnValues()
+	v, _ = av.Value()
+	assert.Nil(t, v)
+	v, _ = cv.Value()
+	assert.Nil(t, v)
+}
+
+func TestNullMoneyJSON(t *testing.T) {
+	b, err := json.Marshal(mustMoney(t, "10", "BRL"))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"amount":"10.00","currency":"BRL"}`, string(b))
+	b, err = json.Marshal(NullMoney{})
+	assert.NoError(t, err)
+	assert.Equal(t, `null`, string(b))
+	//
+	var m NullMoney
+	assert.NoError(t, json.Unmarshal([]byte(`{"amount":"0.10","currency":"USD"}`), &m))
+	assert.Equal(t, "0.10 USD", m.String())
+	assert.NoError(t, json.Unmarshal([]byte(`{"amount":2.5,"currency":"KWD"}`), &m))
+	assert.Equal(t, "2.500 KWD", m.String())
+	assert.NoError(t, json.Unmarshal([]byte(`null`), &m))
+	assert.False(t, m.Valid)
+	assert.Error(t, json.Unmarshal([]byte(`{"amount":"1","currency":"ZZZ"}`), &m))
+	assert.Error(t, json.Unmarshal([]byte(`{"currency":"BRL"}`), &m))
+}
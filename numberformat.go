@@ -0,0 +1,147 @@
+package sqltypes
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// NumberFormat are the options of the Format methods of the decimal and
+// float types. The zero value writes all the fractional digits with the
+// locale grouping.
+type NumberFormat struct {
+	// Fixed writes exactly Decimals fractional digits
+	Fixed    bool
+	Decimals int
+	// MinFraction and MaxFraction bound the fractional digits when not
+	// Fixed; MaxFraction = 0 is no maximum
+	MinFraction int
+	MaxFraction int
+	// Rounding is used when dropping fractional digits
+	Rounding RoundingMode
+	// NoGrouping disables the grouping separator
+	NoGrouping bool
+	// Percent multiplies the number by 100 and appends the percent sign
+	Percent bool
+	// Accounting writes negatives in parentheses: "(1,234.00)"
+	Accounting bool
+}
+
+// FormatDecimal formats d in locale; ParseDecimal(FormatDecimal(d, locale,
+// f), locale) returns d (rounded as set by f).
+func FormatDecimal(d decimal.Decimal, locale string, f NumberFormat) (string, error) {
+	l, ok := NumberLocaleFor(locale)
+	if !ok {
+		return "", fmt.Errorf("unknown number locale '%s'", locale)
+	}
+	return l.format(d, f)
+}
+
+// Format formats d in locale ("1.234.567,89" in pt-BR)
+func (d NullDecimal) Format(locale string, f NumberFormat) (string, error) {
+	return FormatDecimal(d.D(), locale, f)
+}
+
+// Format formats d in locale; NULL is ""
+func (d NullDecimalOpt) Format(locale string, f NumberFormat) (string, error) {
+	if !d.Valid {
+		return "", nil
+	}
+	return FormatDecimal(d.Decimal, locale, f)
+}
+
+// Format formats n in locale. NaN and infinities are an error.
+func (n NullFloat64) Format(locale string, f NumberFormat) (string, error) {
+	v := float64(n)
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return "", fmt.Errorf("cannot format %v", v)
+	}
+	return FormatDecimal(decimal.NewFromFloat(v), locale, f)
+}
+
+func (l *NumberLocale) format(d decimal.Decimal, f NumberFormat) (string, error) {
+	if f.Percent {
+		d = d.Shift(2)
+	}
+	minf := f.MinFraction
+	if f.Fixed {
+		minf = f.Decimals
+	}
+	if f.Fixed || f.MaxFraction > 0 {
+		scale := f.MaxFraction
+		if f.Fixed {
+			scale = f.Decimals
+		}
+		r, err := roundDecimal(d, scale, f.Rounding)
+		if err != nil {
+			return "", err
+		}
+		d = r
+	}
+	ip, fp := d.Abs().String(), ""
+	if i := strings.IndexByte(ip, '.'); i >= 0 {
+		ip, fp = ip[:i], strings.TrimRight(ip[i+1:], "0")
+	}
+	if len(fp) < minf {
+		fp += strings.Repeat("0", minf-len(fp))
+	}
+	var b strings.Builder
+	neg := d.Sign() < 0
+	if neg {
+		if f.Accounting {
+			b.WriteByte('(')
+		} else {
+			b.WriteByte('-')
+		}
+	}
+	if f.NoGrouping {
+		b.WriteString(ip)
+	} else {
+		l.group(&b, ip)
+	}
+	if fp != "" {
+		b.WriteString(l.Decimal)
+		b.WriteString(fp)
+	}
+	if f.Percent {
+		if l.Percent != "" {
+			b.WriteString(l.Percent)
+		} else {
+			b.WriteByte('%')
+		}
+	}
+	if neg && f.Accounting {
+		b.WriteByte(')')
+	}
+	return b.String(), nil
+}
+
+// group writes the digits of ip with the grouping separators
+func (l *NumberLocale) group(b *strings.Builder, ip string) {
+	grouping := l.Grouping
+	if len(grouping) == 0 {
+		grouping = []int{3}
+	}
+	// sizes of the groups, from the right
+	var sizes []int
+	for n, k := len(ip), 0; n > 0; k++ {
+		size := grouping[len(grouping)-1]
+		if k < len(grouping) {
+			size = grouping[k]
+		}
+		if size > n {
+			size = n
+		}
+		sizes = append(sizes, size)
+		n -= size
+	}
+	for i := len(sizes) - 1; i >= 0; i-- {
+		b.WriteString(ip[:sizes[i]])
+		ip = ip[sizes[i]:]
+		if i > 0 {
+			b.WriteString(l.Group)
+		}
+	}
+}
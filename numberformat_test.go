@@ -0,0 +1,85 @@
+package sqltypes
+
+import (
+	"math"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatDecimal(t *testing.T) {
+	d := decimal.RequireFromString("1234567.891")
+	tests := []struct {
+		d      decimal.Decimal
+		locale string
+		f      NumberFormat
+		out    string
+	}{
+		{d, "en", NumberFormat{}, "1,234,567.891"},
+		{d, "pt-BR", NumberFormat{Fixed: true, Decimals: 2}, "1.234.567,89"},
+		{d, "de-CH", NumberFormat{Fixed: true, Decimals: 2}, "1’234’567.89"},
+		{d, "en-IN", NumberFormat{Fixed: true, Decimals: 2}, "12,34,567.89"},
+		{d, "fr", NumberFormat{MaxFraction: 1}, "1\u202f234\u202f567,9"},
+		{d, "en", NumberFormat{NoGrouping: true, Fixed: true}, "1234568"},
+		{d.Neg(), "en", NumberFormat{Fixed: true, Decimals: 2, Accounting: true}, "(1,234,567.89)"},
+		{d.Neg(), "pt-BR", NumberFormat{Fixed: true, Decimals: 2}, "-1.234.567,89"},
+		{decimal.RequireFromString("2.5"), "en", NumberFormat{MinFraction: 2}, "2.50"},
+		{decimal.RequireFromString("2.5"), "en", NumberFormat{MinFraction: 2, MaxFraction: 4}, "2.50"},
+		{decimal.RequireFromString("2.123456"), "en", NumberFormat{MinFraction: 2, MaxFraction: 4}, "2.1235"},
+		{decimal.RequireFromString("2.125"), "en", NumberFormat{Fixed: true, Decimals: 2}, "2.12"},
+		{decimal.RequireFromString("2.125"), "en", NumberFormat{Fixed: true, Decimals: 2, Rounding: RoundHalfUp}, "2.13"},
+		{decimal.RequireFromString("0.125"), "en", NumberFormat{Percent: true}, "12.5%"},
+		{decimal.RequireFromString("0.125"), "de", NumberFormat{Percent: true}, "12,5\u00a0%"},
+		{decimal.RequireFromString("-0.001"), "en", NumberFormat{Fixed: true, Decimals: 2}, "0.00"},
+		{decimal.RequireFromString("100"), "en", NumberFormat{}, "100"},
+		{decimal.RequireFromString("0.5"), "en", NumberFormat{}, "0.5"},
+	}
+	for _, tt := range tests {
+		s, err := FormatDecimal(tt.d, tt.locale, tt.f)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.out, s, "%s %s %+v", tt.d, tt.locale, tt.f)
+	}
+	_, err := FormatDecimal(d, "xx", NumberFormat{})
+	assert.Error(t, err)
+}
+
+func TestFormatRoundTrip(t *testing.T) {
+	formats := []NumberFormat{
+		{},
+		{NoGrouping: true},
+		{Accounting: true},
+		{Percent: true},
+		{Percent: true, Accounting: true},
+	}
+	for _, locale := range []string{"en", "en-IN", "pt-BR", "pt-PT", "de", "de-CH", "fr", "es"} {
+		for _, s := range []string{"0", "1", "-1", "0.5", "1234.5", "-9876543210.0123", "12345678"} {
+			d := decimal.RequireFromString(s)
+			for _, f := range formats {
+				out, err := FormatDecimal(d, locale, f)
+				assert.NoError(t, err)
+				back, err := ParseDecimal(out, locale)
+				if assert.NoError(t, err, "%s %s %q", locale, s, out) {
+					assert.True(t, d.Equal(back), "%s %s %q %s", locale, s, out, back)
+				}
+			}
+		}
+	}
+}
+
+func TestNullFormat(t *testing.T) {
+	s, err := NullDecimal(decimal.RequireFromString("1234.5")).Format("pt-BR", NumberFormat{Fixed: true, Decimals: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, "1.234,50", s)
+	s, err = NullDecimalOpt{}.Format("pt-BR", NumberFormat{})
+	assert.NoError(t, err)
+	assert.Equal(t, "", s)
+	s, err = NullFloat64(0.1).Format("en", NumberFormat{})
+	assert.NoError(t, err)
+	assert.Equal(t, "0.1", s)
+	s, err = NullFloat64(-1234.5).Format("en", NumberFormat{Accounting: true, MinFraction: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, "(1,234.50)", s)
+	_, err = NullFloat64(math.NaN()).Format("en", NumberFormat{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,353 @@
+package sqltypes
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/shopspring/decimal"
+)
+
+// NumberLocale describes how a locale writes numbers
+type NumberLocale struct {
+	Name string
+	// Decimal is the decimal separator
+	Decimal string
+	// Group is the grouping (thousands) separator. A space accepts any
+	// kind of space when parsing.
+	Group string
+	// AltGroups are other grouping separators accepted when parsing
+	AltGroups []string
+	// Grouping are the sizes of the digit groups from the right; the last
+	// one repeats. {3} is 1,234,567 and {3, 2} is 12,34,567 (Indian). Nil
+	// accepts any grouping when parsing.
+	Grouping []int
+	// Currency are the currency symbols of the locale; ParseDecimal strips
+	// them (and ISO 4217 codes) from either side of the number
+	Currency []string
+	// Percent is the percent suffix written by Format; "%" if empty
+	Percent string
+}
+
+// currency symbols stripped in every locale
+var commonCurrency = []string{"US$", "R$", "$", "€", "£", "¥", "₹"}
+
+var numberLocales = struct {
+	sync.RWMutex
+	m map[string]*NumberLocale
+}{m: make(map[string]*NumberLocale)}
+
+var (
+	// used by DecimalFromString
+	looseDotLocale   = NumberLocale{Decimal: ".", Group: ","}
+	looseCommaLocale = NumberLocale{Decimal: ",", Group: "."}
+)
+
+func init() {
+	three := []int{3}
+	en := NumberLocale{Name: "en", Decimal: ".", Group: ",", Grouping: three}
+	enUS := en
+	enUS.Name = "en-US"
+	enGB := en
+	enGB.Name, enGB.Currency = "en-GB", []string{"£"}
+	enIN := en
+	enIN.Name, enIN.Grouping, enIN.Currency = "en-IN", []int{3, 2}, []string{"₹", "Rs.", "Rs"}
+	hiIN := enIN
+	hiIN.Name = "hi-IN"
+	pt := NumberLocale{Name: "pt", Decimal: ",", Group: ".", Grouping: three, Currency: []string{"R$"}}
+	ptBR := pt
+	ptBR.Name = "pt-BR"
+	ptPT := NumberLocale{Name: "pt-PT", Decimal: ",", Group: "\u00a0", AltGroups: []string{"."}, Grouping: three}
+	es := NumberLocale{Name: "es", Decimal: ",", Group: ".", Grouping: three}
+	de := NumberLocale{Name: "de", Decimal: ",", Group: ".", Grouping: three, Percent: "\u00a0%"}
+	deDE := de
+	deDE.Name = "de-DE"
+	deCH := NumberLocale{Name: "de-CH", Decimal: ".", Group: "\u2019", AltGroups: []string{"'"}, Grouping: three, Currency: []string{"CHF", "Fr."}}
+	fr := NumberLocale{Name: "fr", Decimal: ",", Group: "\u202f", Grouping: three, Percent: "\u00a0%"}
+	frFR := fr
+	frFR.Name = "fr-FR"
+	for _, l := range []NumberLocale{en, enUS, enGB, enIN, hiIN, pt, ptBR, ptPT, es, de, deDE, deCH, fr, frFR} {
+		l := l
+		RegisterNumberLocale(&l)
+	}
+}
+
+// RegisterNumberLocale adds (or replaces) a number locale
+func RegisterNumberLocale(l *NumberLocale) {
+	numberLocales.Lock()
+	numberLocales.m[localeKey(l.Name)] = l
+	numberLocales.Unlock()
+}
+
+// NumberLocaleFor returns the locale named name ("pt-BR", "pt_BR"), falling
+// back to its language ("pt"). An empty name is "en".
+func NumberLocaleFor(name string) (*NumberLocale, bool) {
+	if name == "" {
+		name = "en"
+	}
+	k := localeKey(name)
+	numberLocales.RLock()
+	defer numberLocales.RUnlock()
+	if l, ok := numberLocales.m[k]; ok {
+		return l, true
+	}
+	if i := strings.IndexByte(k, '-'); i > 0 {
+		l, ok := numberLocales.m[k[:i]]
+		return l, ok
+	}
+	return nil, false
+}
+
+// ParseDecimal parses a number written in locale: "R$ 1.234,56" (pt-BR),
+// "1’234.56" (de-CH), "12,34,567.89" (en-IN). Currency symbols, ISO 4217
+// codes, a leading or trailing sign, parenthesised negatives ("(10.00)") and
+// scientific notation ("1.5e-3") are accepted. A trailing '%' divides the
+// number by 100. The grouping of the integer part is checked, so "1.5" is an
+// error in pt-BR.
+func ParseDecimal(s, locale string) (decimal.Decimal, error) {
+	l, ok := NumberLocaleFor(locale)
+	if !ok {
+		return decimal.Zero, fmt.Errorf("unknown number locale '%s'", locale)
+	}
+	return l.parse(s)
+}
+
+func (l *NumberLocale) parse(orig string) (decimal.Decimal, error) {
+	invalid := func() (decimal.Decimal, error) {
+		return decimal.Zero, fmt.Errorf("invalid number '%s'", orig)
+	}
+	s := strings.TrimSpace(orig)
+	neg, signed, percent := false, false, false
+	// strip currency, signs, parentheses and percent from both sides, in any
+	// order
+	for {
+		t := strings.TrimSpace(s)
+		if strings.HasSuffix(t, "%") {
+			if percent {
+				return invalid()
+			}
+			percent = true
+			t = strings.TrimSpace(t[:len(t)-1])
+		}
+		if len(t) > 1 && t[0] == '(' && t[len(t)-1] == ')' {
+			if signed {
+				return invalid()
+			}
+			neg, signed = true, true
+			t = strings.TrimSpace(t[1 : len(t)-1])
+		}
+		if n := l.currencyPrefix(t); n > 0 {
+			t = strings.TrimSpace(t[n:])
+		}
+		if n := l.currencySuffix(t); n > 0 {
+			t = strings.TrimSpace(t[:len(t)-n])
+		}
+		if sign, n := signPrefix(t); n > 0 {
+			if signed {
+				return invalid()
+			}
+			neg, signed = sign < 0, true
+			t = t[n:]
+		} else if sign, n := signSuffix(t); n > 0 {
+			if signed {
+				return invalid()
+			}
+			neg, signed = sign < 0, true
+			t = t[:len(t)-n]
+		}
+		if t == s {
+			break
+		}
+		s = t
+	}
+	exp := ""
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		exp = s[i+1:]
+		s = s[:i]
+		e := strings.TrimPrefix(strings.TrimPrefix(exp, "-"), "+")
+		if e == "" || !isDigits(e) {
+			return invalid()
+		}
+	}
+	parts := strings.Split(s, l.Decimal)
+	if len(parts) > 2 {
+		return invalid()
+	}
+	ip, ok := l.ungroup(parts[0])
+	if !ok {
+		return invalid()
+	}
+	fp := ""
+	if len(parts) == 2 {
+		fp = parts[1]
+		if !isDigits(fp) {
+			return invalid()
+		}
+	}
+	if ip == "" && fp == "" {
+		return invalid()
+	}
+	var b strings.Builder
+	if neg {
+		b.WriteByte('-')
+	}
+	if ip == "" {
+		ip = "0"
+	}
+	b.WriteString(ip)
+	if fp != "" {
+		b.WriteByte('.')
+		b.WriteString(fp)
+	}
+	if exp != "" {
+		b.WriteByte('e')
+		b.WriteString(exp)
+	}
+	d, err := decimal.NewFromString(b.String())
+	if err != nil {
+		return invalid()
+	}
+	if percent {
+		d = d.Shift(-2)
+	}
+	return d, nil
+}
+
+// ungroup removes the grouping separators of the integer part s, checking
+// the size of the groups
+func (l *NumberLocale) ungroup(s string) (string, bool) {
+	var b strings.Builder
+	var groups []int
+	n := 0
+	for i := 0; i < len(s); {
+		if c := s[i]; c >= '0' && c <= '9' {
+			b.WriteByte(c)
+			n++
+			i++
+			continue
+		}
+		w := l.groupAt(s[i:])
+		if w == 0 || n == 0 {
+			return "", false
+		}
+		groups = append(groups, n)
+		n = 0
+		i += w
+	}
+	if len(groups) == 0 || l.Grouping == nil {
+		return b.String(), len(groups) == 0 || n > 0
+	}
+	if n == 0 {
+		return "", false
+	}
+	groups = append(groups, n)
+	size := 0
+	for i, k := len(groups)-1, 0; i >= 0; i, k = i-1, k+1 {
+		if k < len(l.Grouping) {
+			size = l.Grouping[k]
+		}
+		if groups[i] > size || (i > 0 && groups[i] != size) {
+			return "", false
+		}
+	}
+	return b.String(), true
+}
+
+// groupAt returns the length of the grouping separator at the start of s
+func (l *NumberLocale) groupAt(s string) int {
+	r, w := utf8.DecodeRuneInString(s)
+	if unicode.IsSpace(r) {
+		if g, _ := utf8.DecodeRuneInString(l.Group); unicode.IsSpace(g) {
+			return w
+		}
+	}
+	if l.Group != "" && strings.HasPrefix(s, l.Group) {
+		return len(l.Group)
+	}
+	for _, g := range l.AltGroups {
+		if g != "" && strings.HasPrefix(s, g) {
+			return len(g)
+		}
+	}
+	return 0
+}
+
+func (l *NumberLocale) currencyPrefix(s string) int {
+	n := 0
+	for _, lists := range [][]string{l.Currency, commonCurrency} {
+		for _, c := range lists {
+			if len(c) > n && strings.HasPrefix(s, c) {
+				n = len(c)
+			}
+		}
+	}
+	if n == 0 && len(s) > 3 && isCurrencyCode(s[:3]) {
+		if r, _ := utf8.DecodeRuneInString(s[3:]); !unicode.IsLetter(r) {
+			n = 3
+		}
+	}
+	return n
+}
+
+func (l *NumberLocale) currencySuffix(s string) int {
+	n := 0
+	for _, lists := range [][]string{l.Currency, commonCurrency} {
+		for _, c := range lists {
+			if len(c) > n && strings.HasSuffix(s, c) {
+				n = len(c)
+			}
+		}
+	}
+	if n == 0 && len(s) > 3 && isCurrencyCode(s[len(s)-3:]) {
+		if r, _ := utf8.DecodeLastRuneInString(s[:len(s)-3]); !unicode.IsLetter(r) {
+			n = 3
+		}
+	}
+	return n
+}
+
+func isCurrencyCode(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 'A' || s[i] > 'Z' {
+			return false
+		}
+	}
+	return len(s) == 3
+}
+
+// signPrefix and signSuffix return the sign (-1 or 1) at the start or end of
+// s and its length. U+2212 is accepted as a minus sign.
+func signPrefix(s string) (int, int) {
+	switch {
+	case strings.HasPrefix(s, "-"):
+		return -1, 1
+	case strings.HasPrefix(s, "−"):
+		return -1, len("−")
+	case strings.HasPrefix(s, "+"):
+		return 1, 1
+	}
+	return 0, 0
+}
+
+func signSuffix(s string) (int, int) {
+	switch {
+	case strings.HasSuffix(s, "-"):
+		return -1, 1
+	case strings.HasSuffix(s, "−"):
+		return -1, len("−")
+	case strings.HasSuffix(s, "+"):
+		return 1, 1
+	}
+	return 0, 0
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
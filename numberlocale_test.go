@@ -0,0 +1,82 @@
+package sqltypes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		in, locale, out string
+	}{
+		{"1234.5", "en", "1234.5"},
+		{"1,234,567.89", "en-US", "1234567.89"},
+		{"1.234", "en", "1.234"},
+		{"1.234", "pt-BR", "1234"},
+		{"1.234.567,89", "pt_BR", "1234567.89"},
+		{"R$ 10,00", "pt-BR", "10"},
+		{"-R$ 10,50", "pt-BR", "-10.5"},
+		{"R$ -10,50", "pt-BR", "-10.5"},
+		{"1’234’567.50", "de-CH", "1234567.5"},
+		{"1'234.50", "de-CH", "1234.5"},
+		{"CHF 1'234.50", "de-CH", "1234.5"},
+		{"12,34,567.89", "en-IN", "1234567.89"},
+		{"₹1,00,000", "en-IN", "100000"},
+		{"1 234 567,5", "fr", "1234567.5"},
+		{"1\u202f234,5 €", "fr-FR", "1234.5"},
+		{"1\u00a0234,5", "fr", "1234.5"},
+		{"(1,234.50)", "en", "-1234.5"},
+		{"$(10.00)", "en", "-10"},
+		{"10.00-", "en", "-10"},
+		{"+10", "en", "10"},
+		{"−3", "en", "-3"},
+		{"1.5e3", "en", "1500"},
+		{"-2,5E-2", "pt-BR", "-0.025"},
+		{".5", "en", "0.5"},
+		{"10 USD", "en", "10"},
+		{"EUR 3,50", "de", "3.5"},
+		{"1.234,5", "de-AT", "1234.5"},
+		{"7", "", "7"},
+	}
+	for _, tt := range tests {
+		d, err := ParseDecimal(tt.in, tt.locale)
+		if assert.NoError(t, err, "%q %s", tt.in, tt.locale) {
+			assert.Equal(t, tt.out, d.String(), "%q %s", tt.in, tt.locale)
+		}
+	}
+}
+
+func TestParseDecimalErrors(t *testing.T) {
+	tests := []struct {
+		in, locale string
+	}{
+		{"", "en"},
+		{"abc", "en"},
+		{"1,5", "en"},
+		{"1.5", "pt-BR"},
+		{"1,23,4", "en"},
+		{"1,234,", "en"},
+		{",234", "en"},
+		{"1,234,567.89", "en-IN"},
+		{"1.2.3", "en"},
+		{"--1", "en"},
+		{"-(1)", "en"},
+		{"(-1)", "en"},
+		{"1e", "en"},
+		{"1e1.5", "en"},
+		{"1.5x", "en"},
+		{"1", "xx"},
+	}
+	for _, tt := range tests {
+		_, err := ParseDecimal(tt.in, tt.locale)
+		assert.Error(t, err, "%q %s", tt.in, tt.locale)
+	}
+}
+
+func TestDecimalFromStringCompat(t *testing.T) {
+	assert.Equal(t, "1.234", DecimalFromString("1,234").String())
+	assert.Equal(t, "1234.5", DecimalFromString("1.234,5").String())
+	assert.Equal(t, "10", DecimalFromString("R$ 10,00").String())
+	assert.Equal(t, "0", DecimalFromString("junk").String())
+}
@@ -0,0 +1,608 @@
+package sqltypes
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// NullIntRange is a range of integers (Postgres int4range/int8range). Like
+// Postgres, ranges are canonicalised to the [) form. Valid = false is NULL.
+type NullIntRange struct {
+	Lower    int64
+	Upper    int64
+	LowerInf bool // unbounded below
+	UpperInf bool // unbounded above
+	LowerInc bool
+	UpperInc bool
+	Empty    bool
+	Valid    bool
+}
+
+// IntRange returns a canonical NullIntRange; bounds is "[)", "[]", "()" or
+// "(]" like the Postgres int8range constructor.
+func IntRange(lower, upper int64, bounds string) (NullIntRange, error) {
+	if !validRangeBounds(bounds) {
+		return NullIntRange{}, fmt.Errorf("invalid range bounds '%s'", bounds)
+	}
+	if lower > upper {
+		return NullIntRange{}, fmt.Errorf("range lower bound %d must be less than or equal to upper bound %d", lower, upper)
+	}
+	r := NullIntRange{
+		Lower:    lower,
+		Upper:    upper,
+		LowerInc: bounds[0] == '[',
+		UpperInc: bounds[1] == ']',
+		Valid:    true,
+	}
+	return r.canonical()
+}
+
+func cmpInts(a, b interface{}) int {
+	x, y := a.(int64), b.(int64)
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+// canonical converts r to [) form
+func (r NullIntRange) canonical() (NullIntRange, error) {
+	if !r.Valid || r.Empty {
+		return r, nil
+	}
+	if r.LowerInf {
+		r.Lower, r.LowerInc = 0, false
+	} else if !r.LowerInc {
+		if r.Lower == math.MaxInt64 {
+			return NullIntRange{}, fmt.Errorf("range bound %d out of range", r.Lower)
+		}
+		r.Lower, r.LowerInc = r.Lower+1, true
+	}
+	if r.UpperInf {
+		r.Upper, r.UpperInc = 0, false
+	} else if r.UpperInc {
+		if r.Upper == math.MaxInt64 {
+			return NullIntRange{}, fmt.Errorf("range bound %d out of range", r.Upper)
+		}
+		r.Upper, r.UpperInc = r.Upper+1, false
+	}
+	return intRangeFromSpan(r.span().normalize()), nil
+}
+
+// mustCanonical is canonical for ranges built by the set operations, whose
+// bounds come from already canonical ranges
+func (r NullIntRange) mustCanonical() NullIntRange {
+	c, err := r.canonical()
+	if err != nil {
+		return r
+	}
+	return c
+}
+
+func (r NullIntRange) span() rangeSpan {
+	return rangeSpan{
+		lower: rangeEnd{val: r.Lower, inf: r.LowerInf, inc: r.LowerInc},
+		upper: rangeEnd{val: r.Upper, inf: r.UpperInf, inc: r.UpperInc},
+		empty: r.Empty || !r.Valid,
+		cmp:   cmpInts,
+	}
+}
+
+func intRangeFromSpan(s rangeSpan) NullIntRange {
+	if s.empty {
+		return NullIntRange{Empty: true, Valid: true}
+	}
+	r := NullIntRange{
+		LowerInf: s.lower.inf,
+		UpperInf: s.upper.inf,
+		LowerInc: s.lower.inc,
+		UpperInc: s.upper.inc,
+		Valid:    true,
+	}
+	if !s.lower.inf {
+		r.Lower = s.lower.val.(int64)
+	}
+	if !s.upper.inf {
+		r.Upper = s.upper.val.(int64)
+	}
+	return r
+}
+
+// Contains reports whether n is in r
+func (r NullIntRange) Contains(n int64) bool {
+	return r.mustCanonical().span().contains(n)
+}
+
+// ContainsRange reports whether o is entirely in r
+func (r NullIntRange) ContainsRange(o NullIntRange) bool {
+	return r.Valid && o.Valid && r.mustCanonical().span().containsRange(o.mustCanonical().span())
+}
+
+// Overlaps reports whether r and o have integers in common
+func (r NullIntRange) Overlaps(o NullIntRange) bool {
+	return r.mustCanonical().span().overlaps(o.mustCanonical().span())
+}
+
+// Adjacent reports whether r and o touch without overlapping
+func (r NullIntRange) Adjacent(o NullIntRange) bool {
+	return r.mustCanonical().span().adjacent(o.mustCanonical().span())
+}
+
+// Intersect returns the integers in both r and o
+func (r NullIntRange) Intersect(o NullIntRange) NullIntRange {
+	if !r.Valid || !o.Valid {
+		return NullIntRange{}
+	}
+	return intRangeFromSpan(r.span().intersect(o.span())).mustCanonical()
+}
+
+// Union returns the integers in r or o. ok is false if r and o neither
+// overlap nor are adjacent.
+func (r NullIntRange) Union(o NullIntRange) (NullIntRange, bool) {
+	if !r.Valid || !o.Valid {
+		return NullIntRange{}, false
+	}
+	s, ok := r.mustCanonical().span().union(o.mustCanonical().span())
+	if !ok {
+		return NullIntRange{}, false
+	}
+	return intRangeFromSpan(s).mustCanonical(), true
+}
+
+// Difference returns the integers in r but not in o. ok is false if o is
+// strictly inside r.
+func (r NullIntRange) Difference(o NullIntRange) (NullIntRange, bool) {
+	if !r.Valid || !o.Valid {
+		return NullIntRange{}, false
+	}
+	s, ok := r.mustCanonical().span().difference(o.mustCanonical().span())
+	if !ok {
+		return NullIntRange{}, false
+	}
+	return intRangeFromSpan(s).mustCanonical(), true
+}
+
+// Len returns the number of integers in r. ok is false if r is unbounded.
+func (r NullIntRange) Len() (n int64, ok bool) {
+	c := r.mustCanonical()
+	if !c.Valid || c.Empty {
+		return 0, true
+	}
+	if c.LowerInf || c.UpperInf {
+		return 0, false
+	}
+	return c.Upper - c.Lower, true
+}
+
+// String returns r in the Postgres range format or "" if NULL
+func (r NullIntRange) String() string {
+	if !r.Valid {
+		return ""
+	}
+	c := r.mustCanonical()
+	return formatRangeText(rangeText{
+		lower:    strconv.FormatInt(c.Lower, 10),
+		upper:    strconv.FormatInt(c.Upper, 10),
+		lowerInf: c.LowerInf,
+		upperInf: c.UpperInf,
+		lowerInc: c.LowerInc,
+		upperInc: c.UpperInc,
+		empty:    c.Empty,
+	})
+}
+
+// Scan implements the Scanner interface.
+func (r *NullIntRange) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*r = NullIntRange{}
+		return nil
+	case []byte:
+		return r.parse(string(v))
+	case string:
+		return r.parse(v)
+	}
+	return fmt.Errorf("unsupported Scan, storing driver.Value type %T into type %T", value, r)
+}
+
+func (r *NullIntRange) parse(s string) error {
+	rt, err := parseRangeText(s)
+	if err != nil {
+		return err
+	}
+	if rt.empty {
+		*r = NullIntRange{Empty: true, Valid: true}
+		return nil
+	}
+	nr := NullIntRange{
+		LowerInf: rt.lowerInf,
+		UpperInf: rt.upperInf,
+		LowerInc: rt.lowerInc,
+		UpperInc: rt.upperInc,
+		Valid:    true,
+	}
+	if !rt.lowerInf {
+		if nr.Lower, err = strconv.ParseInt(strings.TrimSpace(rt.lower), 10, 64); err != nil {
+			return fmt.Errorf("invalid range '%s': %v", s, strconvErr(err))
+		}
+	}
+	if !rt.upperInf {
+		if nr.Upper, err = strconv.ParseInt(strings.TrimSpace(rt.upper), 10, 64); err != nil {
+			return fmt.Errorf("invalid range '%s': %v", s, strconvErr(err))
+		}
+	}
+	return r.set(nr)
+}
+
+// set validates and canonicalises nr into r
+func (r *NullIntRange) set(nr NullIntRange) error {
+	if !nr.LowerInf && !nr.UpperInf && nr.Lower > nr.Upper {
+		return fmt.Errorf("range lower bound %d must be less than or equal to upper bound %d", nr.Lower, nr.Upper)
+	}
+	c, err := nr.canonical()
+	if err != nil {
+		return err
+	}
+	*r = c
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (r NullIntRange) Value() (driver.Value, error) {
+	if !r.Valid {
+		return nil, nil
+	}
+	if _, err := r.canonical(); err != nil {
+		return nil, err
+	}
+	return r.String(), nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (r NullIntRange) MarshalJSON() ([]byte, error) {
+	if !r.Valid {
+		return []byte("null"), nil
+	}
+	c := r.mustCanonical()
+	if c.Empty {
+		return json.Marshal(rangeJSON{Empty: true})
+	}
+	rj := rangeJSON{Lower: json.RawMessage("null"), Upper: json.RawMessage("null"), Bounds: rangeBounds(c.LowerInc, c.UpperInc)}
+	if !c.LowerInf {
+		rj.Lower = json.RawMessage(strconv.FormatInt(c.Lower, 10))
+	}
+	if !c.UpperInf {
+		rj.Upper = json.RawMessage(strconv.FormatInt(c.Upper, 10))
+	}
+	return json.Marshal(rj)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (r *NullIntRange) UnmarshalJSON(v []byte) error {
+	*r = NullIntRange{}
+	rj, ok, err := unmarshalRangeJSON(v, r.parse)
+	if err != nil || !ok {
+		return err
+	}
+	if rj.Empty {
+		*r = NullIntRange{Empty: true, Valid: true}
+		return nil
+	}
+	if !validRangeBounds(rj.Bounds) {
+		return fmt.Errorf("invalid range bounds '%s'", rj.Bounds)
+	}
+	nr := NullIntRange{
+		LowerInc: rj.Bounds[0] == '[',
+		UpperInc: rj.Bounds[1] == ']',
+		Valid:    true,
+	}
+	var lower, upper *int64
+	if err := json.Unmarshal(nonEmptyJSON(rj.Lower), &lower); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(nonEmptyJSON(rj.Upper), &upper); err != nil {
+		return err
+	}
+	nr.LowerInf, nr.UpperInf = lower == nil, upper == nil
+	if lower != nil {
+		nr.Lower = *lower
+	}
+	if upper != nil {
+		nr.Upper = *upper
+	}
+	return r.set(nr)
+}
+
+// nonEmptyJSON returns null for a missing value
+func nonEmptyJSON(v json.RawMessage) json.RawMessage {
+	if len(v) == 0 {
+		return json.RawMessage("null")
+	}
+	return v
+}
+
+// NullDecimalRange is a range of decimals (Postgres numrange). Valid = false
+// is NULL.
+type NullDecimalRange struct {
+	Lower    decimal.Decimal
+	Upper    decimal.Decimal
+	LowerInf bool // unbounded below
+	UpperInf bool // unbounded above
+	LowerInc bool
+	UpperInc bool
+	Empty    bool
+	Valid    bool
+}
+
+// DecimalRange returns a NullDecimalRange; bounds is "[)", "[]", "()" or "(]".
+func DecimalRange(lower, upper decimal.Decimal, bounds string) (NullDecimalRange, error) {
+	if !validRangeBounds(bounds) {
+		return NullDecimalRange{}, fmt.Errorf("invalid range bounds '%s'", bounds)
+	}
+	r := NullDecimalRange{
+		Lower:    lower,
+		Upper:    upper,
+		LowerInc: bounds[0] == '[',
+		UpperInc: bounds[1] == ']',
+		Valid:    true,
+	}
+	return r, r.validate()
+}
+
+func cmpDecimals(a, b interface{}) int {
+	return a.(decimal.Decimal).Cmp(b.(decimal.Decimal))
+}
+
+func (r NullDecimalRange) validate() error {
+	if !r.LowerInf && !r.UpperInf && r.Lower.GreaterThan(r.Upper) {
+		return fmt.Errorf("range lower bound %v must be less than or equal to upper bound %v", r.Lower, r.Upper)
+	}
+	return nil
+}
+
+func (r NullDecimalRange) span() rangeSpan {
+	return rangeSpan{
+		lower: rangeEnd{val: r.Lower, inf: r.LowerInf, inc: r.LowerInc && !r.LowerInf},
+		upper: rangeEnd{val: r.Upper, inf: r.UpperInf, inc: r.UpperInc && !r.UpperInf},
+		empty: r.Empty || !r.Valid,
+		cmp:   cmpDecimals,
+	}
+}
+
+func decimalRangeFromSpan(s rangeSpan) NullDecimalRange {
+	if s.empty {
+		return NullDecimalRange{Empty: true, Valid: true}
+	}
+	r := NullDecimalRange{
+		LowerInf: s.lower.inf,
+		UpperInf: s.upper.inf,
+		LowerInc: s.lower.inc,
+		UpperInc: s.upper.inc,
+		Valid:    true,
+	}
+	if !s.lower.inf {
+		r.Lower = s.lower.val.(decimal.Decimal)
+	}
+	if !s.upper.inf {
+		r.Upper = s.upper.val.(decimal.Decimal)
+	}
+	return r
+}
+
+// IsEmpty reports whether r contains no values, including ranges like
+// (1,1) that are empty without the Empty flag.
+func (r NullDecimalRange) IsEmpty() bool {
+	return r.span().normalize().empty
+}
+
+// Contains reports whether d is in r
+func (r NullDecimalRange) Contains(d decimal.Decimal) bool {
+	return r.span().contains(d)
+}
+
+// ContainsRange reports whether o is entirely in r
+func (r NullDecimalRange) ContainsRange(o NullDecimalRange) bool {
+	return r.Valid && o.Valid && r.span().normalize().containsRange(o.span().normalize())
+}
+
+// Overlaps reports whether r and o have values in common
+func (r NullDecimalRange) Overlaps(o NullDecimalRange) bool {
+	return r.span().overlaps(o.span())
+}
+
+// Adjacent reports whether r and o touch without overlapping
+func (r NullDecimalRange) Adjacent(o NullDecimalRange) bool {
+	return r.span().adjacent(o.span())
+}
+
+// Intersect returns the values in both r and o
+func (r NullDecimalRange) Intersect(o NullDecimalRange) NullDecimalRange {
+	if !r.Valid || !o.Valid {
+		return NullDecimalRange{}
+	}
+	return decimalRangeFromSpan(r.span().intersect(o.span()))
+}
+
+// Union returns the values in r or o. ok is false if r and o neither overlap
+// nor are adjacent.
+func (r NullDecimalRange) Union(o NullDecimalRange) (NullDecimalRange, bool) {
+	if !r.Valid || !o.Valid {
+		return NullDecimalRange{}, false
+	}
+	s, ok := r.span().normalize().union(o.span().normalize())
+	if !ok {
+		return NullDecimalRange{}, false
+	}
+	return decimalRangeFromSpan(s), true
+}
+
+// Difference returns the values in r but not in o. ok is false if o is
+// strictly inside r.
+func (r NullDecimalRange) Difference(o NullDecimalRange) (NullDecimalRange, bool) {
+	if !r.Valid || !o.Valid {
+		return NullDecimalRange{}, false
+	}
+	s, ok := r.span().normalize().difference(o.span().normalize())
+	if !ok {
+		return NullDecimalRange{}, false
+	}
+	return decimalRangeFromSpan(s), true
+}
+
+// Width returns Upper - Lower. ok is false if r is unbounded.
+func (r NullDecimalRange) Width() (decimal.Decimal, bool) {
+	if !r.Valid || r.IsEmpty() {
+		return decimal.Zero, true
+	}
+	if r.LowerInf || r.UpperInf {
+		return decimal.Zero, false
+	}
+	return r.Upper.Sub(r.Lower), true
+}
+
+// String returns r in the Postgres range format or "" if NULL
+func (r NullDecimalRange) String() string {
+	if !r.Valid {
+		return ""
+	}
+	return formatRangeText(rangeText{
+		lower:    r.Lower.String(),
+		upper:    r.Upper.String(),
+		lowerInf: r.LowerInf,
+		upperInf: r.UpperInf,
+		lowerInc: r.LowerInc,
+		upperInc: r.UpperInc,
+		empty:    r.IsEmpty(),
+	})
+}
+
+// Scan implements the Scanner interface.
+func (r *NullDecimalRange) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*r = NullDecimalRange{}
+		return nil
+	case []byte:
+		return r.parse(string(v))
+	case string:
+		return r.parse(v)
+	}
+	return fmt.Errorf("unsupported Scan, storing driver.Value type %T into type %T", value, r)
+}
+
+func (r *NullDecimalRange) parse(s string) error {
+	rt, err := parseRangeText(s)
+	if err != nil {
+		return err
+	}
+	if rt.empty {
+		*r = NullDecimalRange{Empty: true, Valid: true}
+		return nil
+	}
+	nr := NullDecimalRange{
+		LowerInf: rt.lowerInf,
+		UpperInf: rt.upperInf,
+		LowerInc: rt.lowerInc,
+		UpperInc: rt.upperInc,
+		Valid:    true,
+	}
+	var d NullDecimal
+	if !rt.lowerInf {
+		if err := d.Scan(strings.TrimSpace(rt.lower)); err != nil {
+			return fmt.Errorf("invalid range '%s': %v", s, err)
+		}
+		nr.Lower = d.D()
+	}
+	if !rt.upperInf {
+		if err := d.Scan(strings.TrimSpace(rt.upper)); err != nil {
+			return fmt.Errorf("invalid range '%s': %v", s, err)
+		}
+		nr.Upper = d.D()
+	}
+	if err := nr.validate(); err != nil {
+		return err
+	}
+	*r = nr
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (r NullDecimalRange) Value() (driver.Value, error) {
+	if !r.Valid {
+		return nil, nil
+	}
+	return r.String(), nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (r NullDecimalRange) MarshalJSON() ([]byte, error) {
+	if !r.Valid {
+		return []byte("null"), nil
+	}
+	if r.IsEmpty() {
+		return json.Marshal(rangeJSON{Empty: true})
+	}
+	rj := rangeJSON{Lower: json.RawMessage("null"), Upper: json.RawMessage("null"), Bounds: rangeBounds(r.LowerInc, r.UpperInc)}
+	var err error
+	if !r.LowerInf {
+		if rj.Lower, err = NullDecimal(r.Lower).MarshalJSON(); err != nil {
+			return nil, err
+		}
+	}
+	if !r.UpperInf {
+		if rj.Upper, err = NullDecimal(r.Upper).MarshalJSON(); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(rj)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (r *NullDecimalRange) UnmarshalJSON(v []byte) error {
+	*r = NullDecimalRange{}
+	rj, ok, err := unmarshalRangeJSON(v, r.parse)
+	if err != nil || !ok {
+		return err
+	}
+	if rj.Empty {
+		*r = NullDecimalRange{Empty: true, Valid: true}
+		return nil
+	}
+	if !validRangeBounds(rj.Bounds) {
+		return fmt.Errorf("invalid range bounds '%s'", rj.Bounds)
+	}
+	nr := NullDecimalRange{
+		LowerInc: rj.Bounds[0] == '[',
+		UpperInc: rj.Bounds[1] == ']',
+		Valid:    true,
+	}
+	nr.LowerInf = len(rj.Lower) == 0 || string(rj.Lower) == "null"
+	nr.UpperInf = len(rj.Upper) == 0 || string(rj.Upper) == "null"
+	var d NullDecimal
+	if !nr.LowerInf {
+		if err := d.UnmarshalJSON(rj.Lower); err != nil {
+			return err
+		}
+		nr.Lower = d.D()
+	}
+	if !nr.UpperInf {
+		if err := d.UnmarshalJSON(rj.Upper); err != nil {
+			return err
+		}
+		nr.Upper = d.D()
+	}
+	if err := nr.validate(); err != nil {
+		return err
+	}
+	*r = nr
+	return nil
+}
@@ -0,0 +1,122 @@
+package sqltypes
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNullIntRangeScan(t *testing.T) {
+	var r NullIntRange
+	assert.NoError(t, r.Scan("[1,10)"))
+	assert.Equal(t, NullIntRange{Lower: 1, Upper: 10, LowerInc: true, Valid: true}, r)
+	assert.NoError(t, r.Scan([]byte("(0,9]")))
+	assert.Equal(t, "[1,10)", r.String())
+	assert.NoError(t, r.Scan("(,5]"))
+	assert.Equal(t, "(,6)", r.String())
+	assert.NoError(t, r.Scan("(3,4)"))
+	assert.True(t, r.Empty)
+	assert.Equal(t, "empty", r.String())
+	assert.Error(t, r.Scan("[5,1)"))
+	assert.Error(t, r.Scan("[a,1)"))
+	assert.Error(t, r.Scan("[1,9223372036854775807]"))
+	v, err := NullIntRange{Lower: 18, Upper: 65, UpperInc: true, LowerInc: true, Valid: true}.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "[18,66)", v)
+	v, err = NullIntRange{}.Value()
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+}
+
+func TestNullIntRangeOps(t *testing.T) {
+	kids, _ := IntRange(0, 17, "[]")
+	adults, _ := IntRange(18, 64, "[]")
+	seniors := NullIntRange{Lower: 65, LowerInc: true, UpperInf: true, Valid: true}
+	assert.True(t, kids.Adjacent(adults))
+	assert.False(t, kids.Overlaps(adults))
+	assert.True(t, seniors.Contains(120))
+	assert.False(t, adults.Contains(65))
+	u, ok := kids.Union(adults)
+	assert.True(t, ok)
+	assert.Equal(t, "[0,65)", u.String())
+	_, ok = kids.Union(seniors)
+	assert.False(t, ok)
+	teens, _ := IntRange(13, 19, "[)")
+	assert.Equal(t, "[18,19)", teens.Intersect(adults).String())
+	d, ok := teens.Difference(adults)
+	assert.True(t, ok)
+	assert.Equal(t, "[13,18)", d.String())
+	_, ok = u.Difference(teens)
+	assert.False(t, ok)
+	assert.True(t, u.ContainsRange(teens))
+	n, ok := adults.Len()
+	assert.True(t, ok)
+	assert.Equal(t, int64(47), n)
+	_, ok = seniors.Len()
+	assert.False(t, ok)
+	// (1,2) holds no integers
+	none := NullIntRange{Lower: 1, Upper: 2, Valid: true}
+	oneTwo, _ := IntRange(1, 3, "[)")
+	assert.False(t, none.Overlaps(oneTwo))
+	assert.False(t, oneTwo.Overlaps(none))
+	assert.False(t, none.Adjacent(oneTwo))
+	assert.False(t, none.Contains(1))
+	assert.False(t, none.Contains(2))
+	assert.True(t, NullIntRange{Lower: 0, Upper: 1, UpperInc: true, Valid: true}.Contains(1))
+}
+
+func TestNullIntRangeJSON(t *testing.T) {
+	r := NullIntRange{Lower: 65, LowerInc: true, UpperInf: true, Valid: true}
+	bb, err := json.Marshal(r)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"lower":65,"upper":null,"bounds":"[)"}`, string(bb))
+	var r2 NullIntRange
+	assert.NoError(t, json.Unmarshal(bb, &r2))
+	assert.Equal(t, r, r2)
+	assert.NoError(t, json.Unmarshal([]byte(`{"lower":1,"upper":3,"bounds":"[]"}`), &r2))
+	assert.Equal(t, "[1,4)", r2.String())
+	assert.NoError(t, json.Unmarshal([]byte(`"[1,2)"`), &r2))
+	assert.Equal(t, int64(2), r2.Upper)
+	assert.Error(t, json.Unmarshal([]byte(`{"lower":3,"upper":1}`), &r2))
+	for _, b := range []string{"xy", "[", "[)]", ")["} {
+		assert.Error(t, json.Unmarshal([]byte(`{"lower":1,"upper":3,"bounds":"`+b+`"}`), &r2), b)
+	}
+}
+
+func TestNullDecimalRange(t *testing.T) {
+	var r NullDecimalRange
+	assert.NoError(t, r.Scan("[9.99,19.99)"))
+	assert.True(t, r.Contains(decimal.RequireFromString("9.99")))
+	assert.False(t, r.Contains(decimal.RequireFromString("19.99")))
+	assert.Equal(t, "[9.99,19.99)", r.String())
+	w, ok := r.Width()
+	assert.True(t, ok)
+	assert.Equal(t, "10", w.String())
+	//
+	r2, err := DecimalRange(decimal.RequireFromString("19.99"), decimal.RequireFromString("49.99"), "[)")
+	assert.NoError(t, err)
+	assert.True(t, r.Adjacent(r2))
+	u, ok := r.Union(r2)
+	assert.True(t, ok)
+	assert.Equal(t, "[9.99,49.99)", u.String())
+	assert.True(t, r.Intersect(r2).IsEmpty())
+	d, ok := u.Difference(r2)
+	assert.True(t, ok)
+	assert.Equal(t, "[9.99,19.99)", d.String())
+	//
+	assert.NoError(t, r.Scan("(1.5,1.5)"))
+	assert.True(t, r.IsEmpty())
+	assert.Equal(t, "empty", r.String())
+	assert.Error(t, r.Scan("[2,1]"))
+	//
+	bb, err := json.Marshal(r2)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"lower":"19.99","upper":"49.99","bounds":"[)"}`, string(bb))
+	var r3 NullDecimalRange
+	assert.NoError(t, json.Unmarshal(bb, &r3))
+	assert.True(t, r3.Lower.Equal(r2.Lower))
+	assert.True(t, r3.Upper.Equal(r2.Upper))
+	assert.Error(t, json.Unmarshal([]byte(`{"lower":"1","upper":"2","bounds":"xy"}`), &r3))
+}
@@ -0,0 +1,328 @@
+package sqltypes
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Range logic shared by the range types. It follows the Postgres
+// implementation (rangetypes.c): bounds are compared taking into account
+// whether they are infinite, inclusive and lower or upper.
+
+type rangeEnd struct {
+	val interface{}
+	inf bool
+	inc bool
+}
+
+type rangeSpan struct {
+	lower rangeEnd
+	upper rangeEnd
+	empty bool
+	// cmp compares two bound values
+	cmp func(a, b interface{}) int
+}
+
+// cmpBounds compares two bounds; upper1 and upper2 tell whether each is an
+// upper bound
+func (r rangeSpan) cmpBounds(b1 rangeEnd, upper1 bool, b2 rangeEnd, upper2 bool) int {
+	if b1.inf && b2.inf {
+		if upper1 == upper2 {
+			return 0
+		}
+		if upper1 {
+			return 1
+		}
+		return -1
+	}
+	if b1.inf {
+		if upper1 {
+			return 1
+		}
+		return -1
+	}
+	if b2.inf {
+		if upper2 {
+			return -1
+		}
+		return 1
+	}
+	c := r.cmp(b1.val, b2.val)
+	if c != 0 {
+		return c
+	}
+	switch {
+	case !b1.inc && !b2.inc:
+		if upper1 == upper2 {
+			return 0
+		}
+		if upper1 {
+			return -1
+		}
+		return 1
+	case !b1.inc:
+		if upper1 {
+			return -1
+		}
+		return 1
+	case !b2.inc:
+		if upper2 {
+			return 1
+		}
+		return -1
+	}
+	return 0
+}
+
+// normalize makes r empty if its bounds don't enclose anything
+func (r rangeSpan) normalize() rangeSpan {
+	if r.empty || r.lower.inf || r.upper.inf {
+		return r
+	}
+	c := r.cmp(r.lower.val, r.upper.val)
+	if c > 0 || (c == 0 && !(r.lower.inc && r.upper.inc)) {
+		return rangeSpan{empty: true, cmp: r.cmp}
+	}
+	return r
+}
+
+func (r rangeSpan) contains(v interface{}) bool {
+	if r.empty {
+		return false
+	}
+	e := rangeEnd{val: v, inc: true}
+	return r.cmpBounds(r.lower, false, e, false) <= 0 && r.cmpBounds(r.upper, true, e, true) >= 0
+}
+
+func (r rangeSpan) containsRange(o rangeSpan) bool {
+	if o.empty {
+		return true
+	}
+	if r.empty {
+		return false
+	}
+	return r.cmpBounds(r.lower, false, o.lower, false) <= 0 && r.cmpBounds(r.upper, true, o.upper, true) >= 0
+}
+
+func (r rangeSpan) overlaps(o rangeSpan) bool {
+	if r.empty || o.empty {
+		return false
+	}
+	return r.cmpBounds(r.lower, false, o.upper, true) <= 0 && r.cmpBounds(o.lower, false, r.upper, true) <= 0
+}
+
+// adjacent reports whether r ends exactly where o starts (or vice versa)
+func (r rangeSpan) adjacent(o rangeSpan) bool {
+	if r.empty || o.empty {
+		return false
+	}
+	touch := func(upper, lower rangeEnd) bool {
+		if upper.inf || lower.inf {
+			return false
+		}
+		return r.cmp(upper.val, lower.val) == 0 && upper.inc != lower.inc
+	}
+	return touch(r.upper, o.lower) || touch(o.upper, r.lower)
+}
+
+func (r rangeSpan) intersect(o rangeSpan) rangeSpan {
+	if !r.overlaps(o) {
+		return rangeSpan{empty: true, cmp: r.cmp}
+	}
+	n := r
+	if r.cmpBounds(o.lower, false, r.lower, false) > 0 {
+		n.lower = o.lower
+	}
+	if r.cmpBounds(o.upper, true, r.upper, true) < 0 {
+		n.upper = o.upper
+	}
+	return n.normalize()
+}
+
+// union merges r and o if they overlap or are adjacent
+func (r rangeSpan) union(o rangeSpan) (rangeSpan, bool) {
+	if r.empty {
+		return o, true
+	}
+	if o.empty {
+		return r, true
+	}
+	if !r.overlaps(o) && !r.adjacent(o) {
+		return rangeSpan{}, false
+	}
+	n := r
+	if r.cmpBounds(o.lower, false, r.lower, false) < 0 {
+		n.lower = o.lower
+	}
+	if r.cmpBounds(o.upper, true, r.upper, true) > 0 {
+		n.upper = o.upper
+	}
+	return n, true
+}
+
+// difference returns r without o. ok is false if o is strictly inside r, as
+// the result would be two ranges.
+func (r rangeSpan) difference(o rangeSpan) (rangeSpan, bool) {
+	if r.empty || o.empty {
+		return r, true
+	}
+	l1l2 := r.cmpBounds(r.lower, false, o.lower, false)
+	l1u2 := r.cmpBounds(r.lower, false, o.upper, true)
+	u1l2 := r.cmpBounds(r.upper, true, o.lower, false)
+	u1u2 := r.cmpBounds(r.upper, true, o.upper, true)
+	switch {
+	case l1l2 < 0 && u1u2 > 0:
+		return rangeSpan{}, false
+	case l1u2 > 0 || u1l2 < 0:
+		return r, true
+	case l1l2 >= 0 && u1u2 <= 0:
+		return rangeSpan{empty: true, cmp: r.cmp}, true
+	case l1l2 <= 0 && u1l2 >= 0 && u1u2 <= 0:
+		n := r
+		n.upper = rangeEnd{val: o.lower.val, inf: o.lower.inf, inc: !o.lower.inc}
+		return n.normalize(), true
+	}
+	n := r
+	n.lower = rangeEnd{val: o.upper.val, inf: o.upper.inf, inc: !o.upper.inc}
+	return n.normalize(), true
+}
+
+// validRangeBounds reports whether bounds is "[)", "[]", "()" or "(]"
+func validRangeBounds(bounds string) bool {
+	return len(bounds) == 2 && strings.Contains("[(", bounds[:1]) && strings.Contains("])", bounds[1:])
+}
+
+// rangeText is a parsed Postgres range literal
+type rangeText struct {
+	lower, upper       string
+	lowerInf, upperInf bool
+	lowerInc, upperInc bool
+	empty              bool
+}
+
+// parseRangeText parses the Postgres range text format:
+// "empty", "[lower,upper)", "(,upper]", "[\"quoted\",)"
+func parseRangeText(s string) (rangeText, error) {
+	orig := s
+	s = strings.TrimSpace(s)
+	if strings.EqualFold(s, "empty") {
+		return rangeText{empty: true}, nil
+	}
+	var rt rangeText
+	if len(s) < 3 {
+		return rt, fmt.Errorf("invalid range '%s'", orig)
+	}
+	switch s[0] {
+	case '[':
+		rt.lowerInc = true
+	case '(':
+	default:
+		return rt, fmt.Errorf("invalid range '%s': missing left parenthesis or bracket", orig)
+	}
+	s = s[1:]
+	var err error
+	rt.lower, rt.lowerInf, s, err = parseRangeBound(s)
+	if err != nil || len(s) == 0 || s[0] != ',' {
+		return rt, fmt.Errorf("invalid range '%s'", orig)
+	}
+	rt.upper, rt.upperInf, s, err = parseRangeBound(s[1:])
+	if err != nil || len(s) == 0 {
+		return rt, fmt.Errorf("invalid range '%s'", orig)
+	}
+	switch s[0] {
+	case ']':
+		rt.upperInc = true
+	case ')':
+	default:
+		return rt, fmt.Errorf("invalid range '%s': missing right parenthesis or bracket", orig)
+	}
+	if strings.TrimSpace(s[1:]) != "" {
+		return rt, fmt.Errorf("invalid range '%s': junk after right parenthesis or bracket", orig)
+	}
+	// infinite bounds are never inclusive
+	if rt.lowerInf {
+		rt.lowerInc = false
+	}
+	if rt.upperInf {
+		rt.upperInc = false
+	}
+	return rt, nil
+}
+
+// parseRangeBound reads a bound up to the next ',', ')' or ']'. An empty
+// unquoted bound is infinite.
+func parseRangeBound(s string) (val string, inf bool, rest string, err error) {
+	var b strings.Builder
+	quoted, inQuote := false, false
+	i := 0
+	for ; i < len(s); i++ {
+		c := s[i]
+		if !inQuote && (c == ',' || c == ')' || c == ']') {
+			break
+		}
+		switch {
+		case c == '\\':
+			i++
+			if i == len(s) {
+				return "", false, "", fmt.Errorf("unexpected end of range")
+			}
+			b.WriteByte(s[i])
+		case c == '"' && inQuote && i+1 < len(s) && s[i+1] == '"':
+			b.WriteByte('"')
+			i++
+		case c == '"':
+			quoted = true
+			inQuote = !inQuote
+		default:
+			b.WriteByte(c)
+		}
+	}
+	if inQuote {
+		return "", false, "", fmt.Errorf("unterminated quote in range")
+	}
+	if !quoted && b.Len() == 0 {
+		return "", true, s[i:], nil
+	}
+	return b.String(), false, s[i:], nil
+}
+
+// formatRangeText writes a Postgres range literal, quoting bounds if needed
+func formatRangeText(rt rangeText) string {
+	if rt.empty {
+		return "empty"
+	}
+	var b strings.Builder
+	if rt.lowerInc && !rt.lowerInf {
+		b.WriteByte('[')
+	} else {
+		b.WriteByte('(')
+	}
+	if !rt.lowerInf {
+		writeRangeBound(&b, rt.lower)
+	}
+	b.WriteByte(',')
+	if !rt.upperInf {
+		writeRangeBound(&b, rt.upper)
+	}
+	if rt.upperInc && !rt.upperInf {
+		b.WriteByte(']')
+	} else {
+		b.WriteByte(')')
+	}
+	return b.String()
+}
+
+func writeRangeBound(b *strings.Builder, v string) {
+	if v != "" && !strings.ContainsAny(v, "\"\\,()[] \t\n") {
+		b.WriteString(v)
+		return
+	}
+	b.WriteByte('"')
+	for i := 0; i < len(v); i++ {
+		if v[i] == '"' || v[i] == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(v[i])
+	}
+	b.WriteByte('"')
+}
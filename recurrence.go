@@ -0,0 +1,674 @@
+package sqltypes
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency is the FREQ of a recurrence rule
+type Frequency int
+
+// Recurrence frequencies
+const (
+	Secondly Frequency = iota + 1
+	Minutely
+	Hourly
+	Daily
+	Weekly
+	Monthly
+	Yearly
+)
+
+var frequencyNames = map[Frequency]string{
+	Secondly: "SECONDLY",
+	Minutely: "MINUTELY",
+	Hourly:   "HOURLY",
+	Daily:    "DAILY",
+	Weekly:   "WEEKLY",
+	Monthly:  "MONTHLY",
+	Yearly:   "YEARLY",
+}
+
+func (f Frequency) String() string {
+	return frequencyNames[f]
+}
+
+// RecurrenceDay is a BYDAY entry: a weekday with an optional ordinal
+// (1MO is the first Monday, -1FR the last Friday, N = 0 is every one)
+type RecurrenceDay struct {
+	N       int
+	Weekday time.Weekday
+}
+
+var rruleWeekdays = [7]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+func (d RecurrenceDay) String() string {
+	if d.N == 0 {
+		return rruleWeekdays[d.Weekday]
+	}
+	return strconv.Itoa(d.N) + rruleWeekdays[d.Weekday]
+}
+
+// NullRecurrence is an RFC 5545 recurrence rule stored as text, either a bare
+// rule ("FREQ=WEEKLY;BYDAY=MO,WE"), "RRULE:..." or with a start
+// ("DTSTART:20200101T090000Z\nRRULE:..."). Only FREQ, INTERVAL, COUNT,
+// UNTIL, BYDAY, BYMONTHDAY, BYMONTH and WKST are supported; other parts are
+// rejected on Scan. Valid = false is NULL.
+type NullRecurrence struct {
+	Freq       Frequency
+	Interval   int // 0 is 1
+	Count      int // 0 is unlimited
+	Until      time.Time
+	ByDay      []RecurrenceDay
+	ByMonthDay []int
+	ByMonth    []time.Month
+	// Start is the DTSTART of the rule. When zero, the lower bound given to
+	// Between is used instead.
+	Start time.Time
+	Valid bool
+
+	untilDate bool // UNTIL was a date
+	wkst      time.Weekday
+	hasWkst   bool
+}
+
+// ParseRecurrence parses and validates a recurrence rule
+func ParseRecurrence(s string) (NullRecurrence, error) {
+	var r NullRecurrence
+	if err := r.parse(s); err != nil {
+		return NullRecurrence{}, err
+	}
+	return r, nil
+}
+
+func (r *NullRecurrence) parse(s string) error {
+	nr := NullRecurrence{Valid: true, wkst: time.Monday}
+	lines := strings.FieldsFunc(s, func(c rune) bool { return c == '\n' || c == '\r' })
+	rule := ""
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		upper := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(upper, "DTSTART"):
+			t, _, err := parseICalTime(line[len("DTSTART"):])
+			if err != nil {
+				return fmt.Errorf("invalid recurrence DTSTART '%s': %v", line, err)
+			}
+			nr.Start = t
+		case strings.HasPrefix(upper, "RRULE:"):
+			rule = line[len("RRULE:"):]
+		case line != "":
+			rule = line
+		}
+	}
+	if rule == "" {
+		return fmt.Errorf("invalid recurrence '%s': missing RRULE", s)
+	}
+	for _, part := range strings.Split(rule, ";") {
+		if part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("invalid recurrence part '%s'", part)
+		}
+		if err := nr.setPart(strings.ToUpper(kv[0]), strings.ToUpper(kv[1])); err != nil {
+			return err
+		}
+	}
+	if err := nr.validate(); err != nil {
+		return err
+	}
+	*r = nr
+	return nil
+}
+
+func (r *NullRecurrence) setPart(key, val string) error {
+	bad := func() error {
+		return fmt.Errorf("invalid recurrence %s '%s'", key, val)
+	}
+	switch key {
+	case "FREQ":
+		for f, name := range frequencyNames {
+			if name == val {
+				r.Freq = f
+				return nil
+			}
+		}
+		return bad()
+	case "INTERVAL":
+		n, err := strconv.Atoi(val)
+		if err != nil || n < 1 {
+			return bad()
+		}
+		r.Interval = n
+	case "COUNT":
+		n, err := strconv.Atoi(val)
+		if err != nil || n < 1 {
+			return bad()
+		}
+		r.Count = n
+	case "UNTIL":
+		t, isDate, err := parseICalTime(":" + val)
+		if err != nil {
+			return bad()
+		}
+		r.Until, r.untilDate = t, isDate
+	case "WKST":
+		wd, ok := parseRRuleWeekday(val)
+		if !ok {
+			return bad()
+		}
+		r.wkst, r.hasWkst = wd, true
+	case "BYDAY":
+		for _, v := range strings.Split(val, ",") {
+			if len(v) < 2 {
+				return bad()
+			}
+			wd, ok := parseRRuleWeekday(v[len(v)-2:])
+			if !ok {
+				return bad()
+			}
+			d := RecurrenceDay{Weekday: wd}
+			if n := v[:len(v)-2]; n != "" {
+				i, err := strconv.Atoi(n)
+				if err != nil || i == 0 || i < -53 || i > 53 {
+					return bad()
+				}
+				d.N = i
+			}
+			r.ByDay = append(r.ByDay, d)
+		}
+	case "BYMONTHDAY":
+		for _, v := range strings.Split(val, ",") {
+			i, err := strconv.Atoi(v)
+			if err != nil || i == 0 || i < -31 || i > 31 {
+				return bad()
+			}
+			r.ByMonthDay = append(r.ByMonthDay, i)
+		}
+	case "BYMONTH":
+		for _, v := range strings.Split(val, ",") {
+			i, err := strconv.Atoi(v)
+			if err != nil || i < 1 || i > 12 {
+				return bad()
+			}
+			r.ByMonth = append(r.ByMonth, time.Month(i))
+		}
+	default:
+		return fmt.Errorf("unsupported recurrence part '%s'", key)
+	}
+	return nil
+}
+
+func parseRRuleWeekday(s string) (time.Weekday, bool) {
+	for i, name := range rruleWeekdays {
+		if name == s {
+			return time.Weekday(i), true
+		}
+	}
+	return 0, false
+}
+
+// parseICalTime parses ":20200101", ":20200101T090000Z" and
+// ";TZID=America/Sao_Paulo:20200101T090000"
+func parseICalTime(s string) (t time.Time, isDate bool, err error) {
+	i := strings.LastIndexByte(s, ':')
+	if i < 0 {
+		return time.Time{}, false, fmt.Errorf("missing ':'")
+	}
+	params, v := s[:i], s[i+1:]
+	loc := time.UTC
+	for _, p := range strings.Split(params, ";") {
+		if strings.HasPrefix(strings.ToUpper(p), "TZID=") {
+			if loc, err = time.LoadLocation(p[len("TZID="):]); err != nil {
+				return time.Time{}, false, err
+			}
+		}
+	}
+	switch {
+	case len(v) == len("20060102"):
+		t, err = time.ParseInLocation("20060102", v, loc)
+		return t, true, err
+	case strings.HasSuffix(v, "Z"):
+		t, err = time.Parse("20060102T150405Z", v)
+		return t, false, err
+	}
+	t, err = time.ParseInLocation("20060102T150405", v, loc)
+	return t, false, err
+}
+
+func (r NullRecurrence) validate() error {
+	if r.Freq == 0 {
+		return fmt.Errorf("invalid recurrence: missing FREQ")
+	}
+	if r.Count != 0 && !r.Until.IsZero() {
+		return fmt.Errorf("invalid recurrence: COUNT and UNTIL are mutually exclusive")
+	}
+	for _, d := range r.ByDay {
+		if d.N != 0 && r.Freq != Monthly && r.Freq != Yearly {
+			return fmt.Errorf("invalid recurrence: BYDAY %v is only allowed with MONTHLY or YEARLY", d)
+		}
+		if d.N != 0 && r.Freq == Yearly && len(r.ByMonth) > 0 && (d.N > 5 || d.N < -5) {
+			return fmt.Errorf("invalid recurrence: BYDAY %v out of range for BYMONTH", d)
+		}
+	}
+	if r.Freq == Weekly && len(r.ByMonthDay) > 0 {
+		return fmt.Errorf("invalid recurrence: BYMONTHDAY is not allowed with WEEKLY")
+	}
+	return nil
+}
+
+// String returns the rule in RFC 5545 format or "" if NULL
+func (r NullRecurrence) String() string {
+	if !r.Valid {
+		return ""
+	}
+	parts := []string{"FREQ=" + r.Freq.String()}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		if r.untilDate {
+			parts = append(parts, "UNTIL="+r.Until.Format("20060102"))
+		} else {
+			parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+		}
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, d := range r.ByDay {
+			days[i] = d.String()
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, d := range r.ByMonthDay {
+			days[i] = strconv.Itoa(d)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonth) > 0 {
+		months := make([]string, len(r.ByMonth))
+		for i, m := range r.ByMonth {
+			months[i] = strconv.Itoa(int(m))
+		}
+		parts = append(parts, "BYMONTH="+strings.Join(months, ","))
+	}
+	if r.hasWkst {
+		parts = append(parts, "WKST="+rruleWeekdays[r.wkst])
+	}
+	rule := strings.Join(parts, ";")
+	if r.Start.IsZero() {
+		return rule
+	}
+	start := "DTSTART:" + r.Start.UTC().Format("20060102T150405Z")
+	if loc := r.Start.Location(); loc != time.UTC && loc != time.Local && loc.String() != "" {
+		start = "DTSTART;TZID=" + loc.String() + ":" + r.Start.Format("20060102T150405")
+	}
+	return start + "\nRRULE:" + rule
+}
+
+// Scan implements the Scanner interface.
+func (r *NullRecurrence) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*r = NullRecurrence{}
+		return nil
+	case []byte:
+		return r.parse(string(v))
+	case string:
+		return r.parse(v)
+	}
+	return fmt.Errorf("unsupported Scan, storing driver.Value type %T into type %T", value, r)
+}
+
+// Value implements the driver Valuer interface.
+func (r NullRecurrence) Value() (driver.Value, error) {
+	if !r.Valid {
+		return nil, nil
+	}
+	if err := r.validate(); err != nil {
+		return nil, err
+	}
+	return r.String(), nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (r NullRecurrence) MarshalJSON() ([]byte, error) {
+	if !r.Valid {
+		return []byte("null"), nil
+	}
+	return []byte(strconv.Quote(r.String())), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (r *NullRecurrence) UnmarshalJSON(v []byte) error {
+	if len(v) == 0 || string(v) == "null" {
+		*r = NullRecurrence{}
+		return nil
+	}
+	s, err := strconv.Unquote(string(v))
+	if err != nil {
+		return fmt.Errorf("invalid recurrence %s", string(v))
+	}
+	return r.parse(s)
+}
+
+// Between returns the occurrences t with after <= t <= before. The time of
+// day and location come from Start (or after, if Start is zero).
+func (r NullRecurrence) Between(after, before time.Time) []NullTime {
+	var out []NullTime
+	r.each(after, before, func(t time.Time) {
+		out = append(out, NullTime(t))
+	})
+	return out
+}
+
+// BetweenDates returns the dates of the occurrences between after and
+// before (inclusive)
+func (r NullRecurrence) BetweenDates(after, before NullDate) []NullDate {
+	loc := time.UTC
+	if !r.Start.IsZero() {
+		loc = r.Start.Location()
+	}
+	a := time.Date(after.Year(), after.Month(), after.Day(), 0, 0, 0, 0, loc)
+	b := time.Date(before.Year(), before.Month(), before.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, 1).Add(-1)
+	var out []NullDate
+	r.each(a, b, func(t time.Time) {
+		d := DateFromTime(t)
+		if n := len(out); n == 0 || out[n-1] != d {
+			out = append(out, d)
+		}
+	})
+	return out
+}
+
+// each calls fn for each occurrence in [after, before]
+func (r NullRecurrence) each(after, before time.Time, fn func(time.Time)) {
+	if !r.Valid || r.Freq == 0 || before.Before(after) {
+		return
+	}
+	start := r.Start
+	if start.IsZero() {
+		start = after
+	}
+	until := r.Until
+	if r.untilDate {
+		until = time.Date(until.Year(), until.Month(), until.Day(), 0, 0, 0, 0, start.Location()).AddDate(0, 0, 1).Add(-1)
+	}
+	if !until.IsZero() && until.Before(before) {
+		before = until
+	}
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+	n := 0
+	// emit returns false when the recurrence is exhausted
+	emit := func(t time.Time) bool {
+		if t.Before(start) {
+			return true
+		}
+		if t.After(before) {
+			return false
+		}
+		n++
+		if !t.Before(after) {
+			fn(t)
+		}
+		return r.Count == 0 || n < r.Count
+	}
+	if r.Freq <= Hourly {
+		step := time.Duration(interval) * map[Frequency]time.Duration{Secondly: time.Second, Minutely: time.Minute, Hourly: time.Hour}[r.Freq]
+		t := start
+		if r.Count == 0 {
+			t = skipSteps(start, after, step)
+		} else if t, n = r.skipCounted(start, after, step); n >= r.Count {
+			return
+		}
+		for ; !t.After(before); t = t.Add(step) {
+			if r.matchesDay(DateFromTime(t)) && !emit(t) {
+				return
+			}
+		}
+		return
+	}
+	sd := DateFromTime(start)
+	hh, mm, ss := start.Clock()
+	for k := 0; ; k++ {
+		first, days := r.period(sd, k*interval)
+		if first.IsZero() || first.T().After(before.AddDate(0, 0, 1)) {
+			return
+		}
+		for _, d := range days {
+			t := time.Date(d.Year(), d.Month(), d.Day(), hh, mm, ss, start.Nanosecond(), start.Location())
+			if !emit(t) {
+				return
+			}
+		}
+	}
+}
+
+// skipSteps returns the first t + k*step at or after to
+func skipSteps(t, to time.Time, step time.Duration) time.Time {
+	for t.Before(to) {
+		// to.Sub saturates after 292 years, so this may take a few jumps
+		k := to.Sub(t) / step
+		if k == 0 {
+			k = 1
+		}
+		t = t.Add(k * step)
+	}
+	return t
+}
+
+// skipCounted is skipSteps for a rule with COUNT: it also returns how many
+// occurrences come before to, counting them a day at a time.
+func (r NullRecurrence) skipCounted(t, to time.Time, step time.Duration) (time.Time, int) {
+	n := 0
+	for t.Before(to) && n < r.Count {
+		y, m, d := t.Date()
+		next := time.Date(y, m, d+1, 0, 0, 0, 0, t.Location())
+		if next.After(to) {
+			next = to
+		}
+		u := skipSteps(t, next, step)
+		if r.matchesDay(DateFromTime(t)) {
+			n += int(u.Sub(t) / step)
+		}
+		t = u
+	}
+	return t, n
+}
+
+// period returns the first day of the k-th period after the one holding sd
+// and the candidate days in it, sorted
+func (r NullRecurrence) period(sd NullDate, k int) (NullDate, []NullDate) {
+	var first NullDate
+	var days []NullDate
+	switch r.Freq {
+	case Yearly:
+		y := sd.Year() + k
+		if y > 9999 {
+			return NullDate{}, nil
+		}
+		first = packDate(y, 1, 1)
+		days = r.yearDays(y, sd)
+	case Monthly:
+		first = sd.StartOfMonth().AddMonths(k)
+		if !first.IsZero() && r.inByMonth(first.Month()) {
+			days = r.monthDays(first.Year(), first.Month(), sd)
+		}
+	case Weekly:
+		wkst := time.Monday
+		if r.hasWkst {
+			wkst = r.wkst
+		}
+		first = sd.AddDays(-((int(sd.Weekday()) - int(wkst) + 7) % 7)).AddDays(7 * k)
+		for i := 0; i < 7 && !first.IsZero(); i++ {
+			d := first.AddDays(i)
+			if d.IsZero() || !r.inByMonth(d.Month()) {
+				continue
+			}
+			if len(r.ByDay) == 0 && d.Weekday() != sd.Weekday() {
+				continue
+			}
+			if len(r.ByDay) > 0 && !r.matchesDay(d) {
+				continue
+			}
+			days = append(days, d)
+		}
+	case Daily:
+		first = sd.AddDays(k)
+		if !first.IsZero() && r.matchesDay(first) {
+			days = []NullDate{first}
+		}
+	}
+	return first, days
+}
+
+// matchesDay reports whether d passes the BYMONTH, BYMONTHDAY and (ordinal
+// free) BYDAY limits
+func (r NullRecurrence) matchesDay(d NullDate) bool {
+	if !r.inByMonth(d.Month()) {
+		return false
+	}
+	if len(r.ByMonthDay) > 0 {
+		ok := false
+		last := daysIn(d.Year(), d.Month())
+		for _, md := range r.ByMonthDay {
+			if md == d.Day() || (md < 0 && last+md+1 == d.Day()) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	if len(r.ByDay) > 0 {
+		for _, bd := range r.ByDay {
+			if bd.Weekday == d.Weekday() {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func (r NullRecurrence) inByMonth(m time.Month) bool {
+	if len(r.ByMonth) == 0 {
+		return true
+	}
+	for _, bm := range r.ByMonth {
+		if bm == m {
+			return true
+		}
+	}
+	return false
+}
+
+func (r NullRecurrence) yearDays(y int, sd NullDate) []NullDate {
+	switch {
+	case len(r.ByMonth) == 0 && len(r.ByMonthDay) == 0 && len(r.ByDay) == 0:
+		if isValidDate(y, int(sd.Month()), sd.Day()) {
+			return []NullDate{packDate(y, int(sd.Month()), sd.Day())}
+		}
+		return nil
+	case len(r.ByMonth) == 0 && len(r.ByMonthDay) == 0:
+		return expandByDay(packDate(y, 1, 1), packDate(y, 12, 31), r.ByDay)
+	}
+	var days []NullDate
+	for m := time.January; m <= time.December; m++ {
+		if r.inByMonth(m) {
+			days = append(days, r.monthDays(y, m, sd)...)
+		}
+	}
+	return days
+}
+
+func (r NullRecurrence) monthDays(y int, m time.Month, sd NullDate) []NullDate {
+	last := daysIn(y, m)
+	switch {
+	case len(r.ByMonthDay) > 0:
+		var byDay map[NullDate]bool
+		if len(r.ByDay) > 0 {
+			byDay = make(map[NullDate]bool)
+			for _, d := range expandByDay(packDate(y, int(m), 1), packDate(y, int(m), last), r.ByDay) {
+				byDay[d] = true
+			}
+		}
+		var days []NullDate
+		for _, md := range r.ByMonthDay {
+			if md < 0 {
+				md = last + md + 1
+			}
+			if md < 1 || md > last {
+				continue
+			}
+			d := packDate(y, int(m), md)
+			if byDay == nil || byDay[d] {
+				days = append(days, d)
+			}
+		}
+		return sortDates(days)
+	case len(r.ByDay) > 0:
+		return expandByDay(packDate(y, int(m), 1), packDate(y, int(m), last), r.ByDay)
+	}
+	if sd.Day() > last {
+		return nil
+	}
+	return []NullDate{packDate(y, int(m), sd.Day())}
+}
+
+// expandByDay returns the days between first and last (inclusive) matching
+// any of bd, sorted
+func expandByDay(first, last NullDate, bd []RecurrenceDay) []NullDate {
+	set := make(map[NullDate]bool)
+	for _, b := range bd {
+		// first matching weekday in the span
+		d0 := first.AddDays((int(b.Weekday) - int(first.Weekday()) + 7) % 7)
+		switch {
+		case b.N == 0:
+			for d := d0; !d.After(last) && !d.IsZero(); d = d.AddDays(7) {
+				set[d] = true
+			}
+		case b.N > 0:
+			if d := d0.AddDays(7 * (b.N - 1)); !d.After(last) {
+				set[d] = true
+			}
+		default:
+			dl := last.AddDays(-((int(last.Weekday()) - int(b.Weekday) + 7) % 7))
+			if d := dl.AddDays(7 * (b.N + 1)); !d.Before(first) {
+				set[d] = true
+			}
+		}
+	}
+	days := make([]NullDate, 0, len(set))
+	for d := range set {
+		days = append(days, d)
+	}
+	return sortDates(days)
+}
+
+func sortDates(days []NullDate) []NullDate {
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	// BYMONTHDAY may repeat a day (1 and -31)
+	out := days[:0]
+	for _, d := range days {
+		if len(out) == 0 || d != out[len(out)-1] {
+			out = append(out, d)
+		}
+	}
+	return out
+}
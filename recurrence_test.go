@@ -0,0 +1,127 @@
+package sqltypes
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func dates(ss ...string) []NullDate {
+	out := make([]NullDate, len(ss))
+	for i, s := range ss {
+		out[i] = DateFromString(s)
+	}
+	return out
+}
+
+func TestNullRecurrenceScan(t *testing.T) {
+	var r NullRecurrence
+	assert.NoError(t, r.Scan("RRULE:FREQ=MONTHLY;INTERVAL=2;BYDAY=-1FR;COUNT=3"))
+	assert.Equal(t, Monthly, r.Freq)
+	assert.Equal(t, 2, r.Interval)
+	assert.Equal(t, []RecurrenceDay{{N: -1, Weekday: time.Friday}}, r.ByDay)
+	assert.Equal(t, "FREQ=MONTHLY;INTERVAL=2;COUNT=3;BYDAY=-1FR", r.String())
+	assert.NoError(t, r.Scan([]byte("DTSTART:20200101T090000Z\nRRULE:FREQ=WEEKLY;BYDAY=MO,WE;UNTIL=20200131")))
+	assert.Equal(t, time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC), r.Start)
+	v, err := r.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "DTSTART:20200101T090000Z\nRRULE:FREQ=WEEKLY;UNTIL=20200131;BYDAY=MO,WE", v)
+	//
+	assert.Error(t, r.Scan("BYDAY=MO"))
+	assert.Error(t, r.Scan("FREQ=FORTNIGHTLY"))
+	assert.Error(t, r.Scan("FREQ=DAILY;COUNT=2;UNTIL=20200101"))
+	assert.Error(t, r.Scan("FREQ=WEEKLY;BYDAY=1MO"))
+	assert.Error(t, r.Scan("FREQ=MONTHLY;BYMONTHDAY=32"))
+	assert.Error(t, r.Scan("FREQ=DAILY;BYHOUR=9"))
+	assert.Error(t, r.Scan("FREQ=DAILY;INTERVAL=0"))
+	assert.NoError(t, r.Scan(nil))
+	assert.False(t, r.Valid)
+}
+
+func TestNullRecurrenceBetween(t *testing.T) {
+	r, err := ParseRecurrence("DTSTART:20200101T090000Z\nRRULE:FREQ=WEEKLY;BYDAY=MO,WE;COUNT=4")
+	assert.NoError(t, err)
+	occ := r.Between(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.Len(t, occ, 4)
+	assert.Equal(t, time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC), occ[0].T())
+	assert.Equal(t, time.Date(2020, 1, 6, 9, 0, 0, 0, time.UTC), occ[1].T())
+	assert.Equal(t, time.Date(2020, 1, 13, 9, 0, 0, 0, time.UTC), occ[3].T())
+	// COUNT counts from DTSTART even when after is later
+	occ = r.Between(time.Date(2020, 1, 7, 0, 0, 0, 0, time.UTC), time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.Len(t, occ, 2)
+}
+
+func TestNullRecurrenceSubDailySkip(t *testing.T) {
+	day := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+	r, err := ParseRecurrence("DTSTART:00010101T000007Z\nRRULE:FREQ=SECONDLY;INTERVAL=10")
+	assert.NoError(t, err)
+	occ := r.Between(day, day.Add(time.Minute))
+	assert.Len(t, occ, 6)
+	assert.Equal(t, day.Add(7*time.Second), occ[0].T())
+	assert.Equal(t, day.Add(57*time.Second), occ[5].T())
+	// COUNT still counts from DTSTART, a day at a time
+	r, err = ParseRecurrence("DTSTART:20000101T000000Z\nRRULE:FREQ=MINUTELY;COUNT=10000000;BYDAY=SU")
+	assert.NoError(t, err)
+	occ = r.Between(day, day.Add(time.Hour))
+	assert.Len(t, occ, 61)
+	assert.Equal(t, day, occ[0].T())
+	assert.Equal(t, day.Add(time.Hour), occ[60].T())
+	r.Count = 3000
+	assert.Empty(t, r.Between(day, day.Add(time.Hour)))
+	r.Count = 1052*1440 + 30 // 1052 Sundays before March 1st
+	occ = r.Between(day, day.Add(time.Hour))
+	assert.Len(t, occ, 30)
+	assert.Equal(t, day.Add(29*time.Minute), occ[29].T())
+}
+
+func TestNullRecurrenceBetweenDates(t *testing.T) {
+	from, to := Date(2020, time.January, 1), Date(2020, time.December, 31)
+	cases := []struct {
+		rule string
+		want []NullDate
+	}{
+		{"DTSTART:20200131T000000Z\nRRULE:FREQ=MONTHLY;COUNT=4",
+			dates("2020-01-31", "2020-03-31", "2020-05-31", "2020-07-31")},
+		{"DTSTART:20200101T000000Z\nRRULE:FREQ=MONTHLY;BYDAY=-1FR;BYMONTH=1,2,3",
+			dates("2020-01-31", "2020-02-28", "2020-03-27")},
+		{"DTSTART:20200101T000000Z\nRRULE:FREQ=MONTHLY;BYDAY=FR;BYMONTHDAY=13",
+			dates("2020-03-13", "2020-11-13")},
+		{"DTSTART:20200101T000000Z\nRRULE:FREQ=YEARLY;BYMONTH=11;BYDAY=4TH",
+			dates("2020-11-26")},
+		{"DTSTART:20200101T000000Z\nRRULE:FREQ=YEARLY;BYDAY=20MO",
+			dates("2020-05-18")},
+		{"DTSTART:20200101T000000Z\nRRULE:FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=3",
+			dates("2020-01-31", "2020-02-29", "2020-03-31")},
+		{"DTSTART:20200101T000000Z\nRRULE:FREQ=DAILY;INTERVAL=10;UNTIL=20200201",
+			dates("2020-01-01", "2020-01-11", "2020-01-21", "2020-01-31")},
+		{"DTSTART:20200101T000000Z\nRRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,TH;COUNT=4",
+			dates("2020-01-02", "2020-01-14", "2020-01-16", "2020-01-28")},
+		{"DTSTART:20160229T000000Z\nRRULE:FREQ=YEARLY",
+			dates("2020-02-29")},
+		{"DTSTART:20200101T220000Z\nRRULE:FREQ=HOURLY;INTERVAL=1;COUNT=3",
+			dates("2020-01-01", "2020-01-02")},
+	}
+	for _, c := range cases {
+		r, err := ParseRecurrence(c.rule)
+		assert.NoError(t, err, c.rule)
+		assert.Equal(t, c.want, r.BetweenDates(from, to), c.rule)
+	}
+	// without DTSTART the lower bound is the start
+	r, err := ParseRecurrence("FREQ=WEEKLY")
+	assert.NoError(t, err)
+	assert.Equal(t, dates("2020-01-01", "2020-01-08"), r.BetweenDates(from, Date(2020, time.January, 14)))
+}
+
+func TestNullRecurrenceJSON(t *testing.T) {
+	str := struct {
+		R NullRecurrence `json:"r"`
+	}{}
+	assert.NoError(t, json.Unmarshal([]byte(`{"r":"FREQ=DAILY;BYMONTH=12"}`), &str))
+	assert.Equal(t, []time.Month{time.December}, str.R.ByMonth)
+	bb, err := json.Marshal(str)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"r":"FREQ=DAILY;BYMONTH=12"}`, string(bb))
+	assert.Error(t, json.Unmarshal([]byte(`{"r":"FREQ=DAILY;BYSETPOS=1"}`), &str))
+}
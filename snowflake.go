@@ -0,0 +1,183 @@
+package sqltypes
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// SnowflakeLayout describes the bits of a snowflake ID: from the top, a
+// sign bit, the timestamp in Unit since Epoch, NodeBits of node and SeqBits
+// of sequence. The zero value is TwitterSnowflake.
+type SnowflakeLayout struct {
+	Epoch    time.Time
+	Unit     time.Duration
+	NodeBits uint
+	SeqBits  uint
+}
+
+// TwitterSnowflake is the layout of Twitter IDs: milliseconds since
+// 2010-11-04 01:42:54.657 UTC, 10 node bits and 12 sequence bits
+var TwitterSnowflake = SnowflakeLayout{
+	Epoch:    time.Unix(1288834974, 657*int64(time.Millisecond)).UTC(),
+	Unit:     time.Millisecond,
+	NodeBits: 10,
+	SeqBits:  12,
+}
+
+func (l SnowflakeLayout) orDefault() SnowflakeLayout {
+	if l == (SnowflakeLayout{}) {
+		return TwitterSnowflake
+	}
+	if l.Unit <= 0 {
+		l.Unit = time.Millisecond
+	}
+	return l
+}
+
+// NullSnowflake is a snowflake ID, stored as an int64 (BIGINT). It is
+// marshaled to JSON as a string, as JavaScript numbers can't hold it.
+// Valid = false is NULL.
+type NullSnowflake struct {
+	ID     int64
+	Valid  bool
+	Layout SnowflakeLayout
+}
+
+// Time returns the timestamp of the ID
+func (s NullSnowflake) Time() NullTime {
+	if !s.Valid {
+		return NullTime{}
+	}
+	l := s.Layout.orDefault()
+	units := s.ID >> (l.NodeBits + l.SeqBits)
+	return NullTime(l.Epoch.Add(time.Duration(units) * l.Unit))
+}
+
+// Node returns the node of the ID
+func (s NullSnowflake) Node() int64 {
+	l := s.Layout.orDefault()
+	return s.ID >> l.SeqBits & (1<<l.NodeBits - 1)
+}
+
+// Sequence returns the sequence of the ID
+func (s NullSnowflake) Sequence() int64 {
+	l := s.Layout.orDefault()
+	return s.ID & (1<<l.SeqBits - 1)
+}
+
+// String returns the ID in base 10 or "" if NULL
+func (s NullSnowflake) String() string {
+	if !s.Valid {
+		return ""
+	}
+	return strconv.FormatInt(s.ID, 10)
+}
+
+// SnowflakeGenerator generates increasing snowflake IDs for a node. When the
+// sequence of a time unit is exhausted, or the clock goes back, it keeps
+// counting on the last timestamp used. It is safe for concurrent use.
+type SnowflakeGenerator struct {
+	layout SnowflakeLayout
+	node   int64
+	mu     sync.Mutex
+	last   int64
+	seq    int64
+}
+
+// NewSnowflakeGenerator returns a generator of IDs in layout for node
+func NewSnowflakeGenerator(layout SnowflakeLayout, node int64) (*SnowflakeGenerator, error) {
+	l := layout.orDefault()
+	if l.NodeBits+l.SeqBits > 62 {
+		return nil, fmt.Errorf("invalid snowflake layout: %d node bits and %d sequence bits", l.NodeBits, l.SeqBits)
+	}
+	if node < 0 || node >= 1<<l.NodeBits {
+		return nil, fmt.Errorf("invalid snowflake node %d (%d bits)", node, l.NodeBits)
+	}
+	return &SnowflakeGenerator{layout: l, node: node, last: -1}, nil
+}
+
+// Next returns an ID greater than the ones generated before
+func (g *SnowflakeGenerator) Next() (NullSnowflake, error) {
+	l := g.layout
+	now := int64(time.Since(l.Epoch) / l.Unit)
+	if now < 0 {
+		return NullSnowflake{}, fmt.Errorf("time is before the snowflake epoch")
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if now > g.last {
+		g.last, g.seq = now, 0
+	} else {
+		g.seq++
+		if g.seq == 1<<l.SeqBits {
+			g.last, g.seq = g.last+1, 0
+		}
+	}
+	if g.last >= 1<<(63-l.NodeBits-l.SeqBits) {
+		return NullSnowflake{}, fmt.Errorf("snowflake timestamp overflow")
+	}
+	id := g.last<<(l.NodeBits+l.SeqBits) | g.node<<l.SeqBits | g.seq
+	return NullSnowflake{ID: id, Valid: true, Layout: g.layout}, nil
+}
+
+// Scan implements the Scanner interface.
+func (s *NullSnowflake) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		s.ID, s.Valid = 0, false
+		return nil
+	case int64:
+		s.ID, s.Valid = v, true
+		return nil
+	case []byte:
+		return s.parse(string(v))
+	case string:
+		return s.parse(v)
+	}
+	return fmt.Errorf("unsupported Scan, storing driver.Value type %T into type %T", value, s)
+}
+
+func (s *NullSnowflake) parse(v string) error {
+	id, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid snowflake '%s'", v)
+	}
+	s.ID, s.Valid = id, true
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (s NullSnowflake) Value() (driver.Value, error) {
+	if !s.Valid {
+		return nil, nil
+	}
+	return s.ID, nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (s NullSnowflake) MarshalJSON() ([]byte, error) {
+	if !s.Valid {
+		return []byte("null"), nil
+	}
+	return []byte(`"` + strconv.FormatInt(s.ID, 10) + `"`), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts a string or a
+// number.
+func (s *NullSnowflake) UnmarshalJSON(v []byte) error {
+	if len(v) == 0 || string(v) == "null" {
+		s.ID, s.Valid = 0, false
+		return nil
+	}
+	str := string(v)
+	if str[0] == '"' {
+		var err error
+		if str, err = strconv.Unquote(str); err != nil {
+			return fmt.Errorf("invalid snowflake %s", string(v))
+		}
+	}
+	return s.parse(str)
+}
@@ -0,0 +1,91 @@
+package sqltypes
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNullSnowflake(t *testing.T) {
+	s := NullSnowflake{ID: 1212092628029698048, Valid: true}
+	assert.Equal(t, "2019-12-31 19:26:16.771 +0000 UTC", s.Time().T().String())
+	assert.Equal(t, int64(327), s.Node())
+	assert.Equal(t, int64(0), s.Sequence())
+	v, err := s.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1212092628029698048), v)
+	//
+	var n NullSnowflake
+	assert.NoError(t, n.Scan([]byte("1212092628029698048")))
+	assert.Equal(t, s.ID, n.ID)
+	assert.NoError(t, n.Scan(int64(42)))
+	assert.Equal(t, int64(42), n.ID)
+	assert.NoError(t, n.Scan(nil))
+	assert.False(t, n.Valid)
+	assert.Error(t, n.Scan("x"))
+	assert.Error(t, n.Scan(1.5))
+	//
+	b, err := json.Marshal(struct{ A, B NullSnowflake }{A: s})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"A":"1212092628029698048","B":null}`, string(b))
+	var j struct{ A, B NullSnowflake }
+	assert.NoError(t, json.Unmarshal([]byte(`{"A":"1212092628029698048","B":1}`), &j))
+	assert.Equal(t, s.ID, j.A.ID)
+	assert.Equal(t, int64(1), j.B.ID)
+}
+
+func TestSnowflakeGenerator(t *testing.T) {
+	layout := SnowflakeLayout{
+		Epoch:    time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		Unit:     10 * time.Millisecond,
+		NodeBits: 5,
+		SeqBits:  3,
+	}
+	g, err := NewSnowflakeGenerator(layout, 21)
+	assert.NoError(t, err)
+	before := time.Now().Add(-10 * time.Millisecond)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	seen := make(map[int64]bool)
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				s, err := g.Next()
+				assert.NoError(t, err)
+				mu.Lock()
+				assert.False(t, seen[s.ID])
+				seen[s.ID] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Len(t, seen, 2000)
+	//
+	s, err := g.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(21), s.Node())
+	assert.False(t, s.Time().T().Before(before))
+	prev := s
+	for i := 0; i < 100; i++ {
+		s, _ = g.Next()
+		assert.True(t, s.ID > prev.ID)
+		prev = s
+	}
+	//
+	_, err = NewSnowflakeGenerator(layout, 32)
+	assert.Error(t, err)
+	_, err = NewSnowflakeGenerator(SnowflakeLayout{NodeBits: 40, SeqBits: 30}, 0)
+	assert.Error(t, err)
+	tw, err := NewSnowflakeGenerator(SnowflakeLayout{}, 1023)
+	assert.NoError(t, err)
+	s, err = tw.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1023), s.Node())
+	assert.WithinDuration(t, time.Now(), s.Time().T(), time.Second)
+}
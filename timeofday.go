@@ -0,0 +1,177 @@
+package sqltypes
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NullTimeOfDay is a time of day for SQL TIME columns (Valid = false is NULL)
+type NullTimeOfDay struct {
+	Hour       int
+	Minute     int
+	Second     int
+	Nanosecond int
+	Valid      bool
+}
+
+const oneDay = 24 * time.Hour
+
+// Duration returns the time elapsed since midnight.
+func (t NullTimeOfDay) Duration() time.Duration {
+	return time.Duration(t.Hour)*time.Hour +
+		time.Duration(t.Minute)*time.Minute +
+		time.Duration(t.Second)*time.Second +
+		time.Duration(t.Nanosecond)
+}
+
+// Add returns t+d, wrapping around midnight.
+func (t NullTimeOfDay) Add(d time.Duration) NullTimeOfDay {
+	if !t.Valid {
+		return t
+	}
+	v := (t.Duration() + d%oneDay + oneDay) % oneDay
+	return timeOfDayFromDuration(v)
+}
+
+// Before reports whether t is earlier in the day than u.
+func (t NullTimeOfDay) Before(u NullTimeOfDay) bool {
+	return t.Duration() < u.Duration()
+}
+
+// After reports whether t is later in the day than u.
+func (t NullTimeOfDay) After(u NullTimeOfDay) bool {
+	return t.Duration() > u.Duration()
+}
+
+// On combines the date d with t in loc. It returns a zero NullTime if
+// either is NULL.
+func (t NullTimeOfDay) On(d NullDate, loc *time.Location) NullTime {
+	if !t.Valid || d.IsZero() {
+		return NullTime{}
+	}
+	yy, mm, dd := d.YMD()
+	return NullTime(time.Date(yy, time.Month(mm), dd, t.Hour, t.Minute, t.Second, t.Nanosecond, loc))
+}
+
+// String returns the time as "15:04:05[.999999999]" or "" if NULL.
+func (t NullTimeOfDay) String() string {
+	if !t.Valid {
+		return ""
+	}
+	s := fmt.Sprintf("%02d:%02d:%02d", t.Hour, t.Minute, t.Second)
+	if t.Nanosecond != 0 {
+		s += strings.TrimRight(fmt.Sprintf(".%09d", t.Nanosecond), "0")
+	}
+	return s
+}
+
+// Scan implements the Scanner interface.
+func (t *NullTimeOfDay) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*t = NullTimeOfDay{}
+		return nil
+	case time.Time:
+		// drivers return TIME as a time.Time on 0000-01-01
+		*t = TimeOfDay(v.Hour(), v.Minute(), v.Second(), v.Nanosecond())
+		return nil
+	case []byte:
+		return t.parse(string(v))
+	case string:
+		return t.parse(v)
+	}
+	return fmt.Errorf("unsupported Scan, storing driver.Value type %T into type %T", value, t)
+}
+
+// Value implements the driver Valuer interface.
+func (t NullTimeOfDay) Value() (driver.Value, error) {
+	if !t.Valid {
+		return nil, nil
+	}
+	return t.String(), nil
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (t NullTimeOfDay) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (t *NullTimeOfDay) UnmarshalText(v []byte) error {
+	if len(v) == 0 {
+		*t = NullTimeOfDay{}
+		return nil
+	}
+	return t.parse(string(v))
+}
+
+// MarshalJSON implements json.Marshaler
+func (t NullTimeOfDay) MarshalJSON() ([]byte, error) {
+	if !t.Valid {
+		return []byte("null"), nil
+	}
+	return []byte(strconv.Quote(t.String())), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (t *NullTimeOfDay) UnmarshalJSON(v []byte) error {
+	if len(v) == 0 || string(v) == "null" {
+		*t = NullTimeOfDay{}
+		return nil
+	}
+	s, err := strconv.Unquote(string(v))
+	if err != nil {
+		return fmt.Errorf("invalid time of day %s", string(v))
+	}
+	return t.UnmarshalText([]byte(s))
+}
+
+// parse accepts "15:04[:05[.fffffffff]]"
+func (t *NullTimeOfDay) parse(s string) error {
+	s = strings.TrimSpace(s)
+	frac := ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		s, frac = s[:i], s[i+1:]
+	}
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return fmt.Errorf("invalid time of day '%s'", s)
+	}
+	var hms [3]int
+	for i, p := range parts {
+		if p == "" || len(p) > 2 || !isDigits(p) {
+			return fmt.Errorf("invalid time of day '%s'", s)
+		}
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return fmt.Errorf("invalid time of day '%s'", s)
+		}
+		hms[i] = n
+	}
+	ns := 0
+	if frac != "" {
+		if !isDigits(frac) {
+			return fmt.Errorf("invalid time of day '%s.%s'", s, frac)
+		}
+		if len(frac) > 9 {
+			frac = frac[:9]
+		}
+		n, err := strconv.Atoi(frac + strings.Repeat("0", 9-len(frac)))
+		if err != nil {
+			return fmt.Errorf("invalid time of day '%s.%s'", s, frac)
+		}
+		ns = n
+	}
+	if hms[0] > 23 || hms[1] > 59 || hms[2] > 59 {
+		return fmt.Errorf("invalid time of day '%s'", s)
+	}
+	*t = TimeOfDay(hms[0], hms[1], hms[2], ns)
+	return nil
+}
+
+func timeOfDayFromDuration(d time.Duration) NullTimeOfDay {
+	return TimeOfDay(int(d/time.Hour), int(d/time.Minute%60), int(d/time.Second%60), int(d%time.Second))
+}
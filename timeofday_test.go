@@ -0,0 +1,56 @@
+package sqltypes
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNullTimeOfDayScan(t *testing.T) {
+	var td NullTimeOfDay
+	assert.NoError(t, td.Scan("15:04:05"))
+	assert.Equal(t, TimeOfDay(15, 4, 5, 0), td)
+	assert.NoError(t, td.Scan([]byte("08:30:00.125")))
+	assert.Equal(t, TimeOfDay(8, 30, 0, 125000000), td)
+	assert.Equal(t, "08:30:00.125", td.String())
+	assert.NoError(t, td.Scan(time.Date(0, 1, 1, 22, 15, 0, 0, time.UTC)))
+	assert.Equal(t, TimeOfDay(22, 15, 0, 0), td)
+	assert.Error(t, td.Scan("25:00:00"))
+	assert.Error(t, td.Scan("ab:cd"))
+	for _, in := range []string{"+1:00", "12:-0", "12:00:00.-5", "12:00:00.+5", "12:00:+1", "12::00", "12:00:00.5x", "1:00.1234567890x"} {
+		assert.Error(t, td.Scan(in), in)
+	}
+	assert.NoError(t, td.Scan(nil))
+	assert.False(t, td.Valid)
+	v, err := td.Value()
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+}
+
+func TestNullTimeOfDayAdd(t *testing.T) {
+	td := TimeOfDay(23, 30, 0, 0)
+	assert.Equal(t, TimeOfDay(0, 15, 0, 0), td.Add(45*time.Minute))
+	assert.Equal(t, TimeOfDay(23, 0, 0, 0), TimeOfDay(1, 0, 0, 0).Add(-2*time.Hour))
+	assert.Equal(t, td, td.Add(48*time.Hour))
+	assert.True(t, TimeOfDay(1, 0, 0, 0).Before(td))
+}
+
+func TestNullTimeOfDayOn(t *testing.T) {
+	tt := TimeOfDay(9, 45, 0, 0).On(DateFromString("2019-07-22"), time.UTC)
+	assert.Equal(t, time.Date(2019, 7, 22, 9, 45, 0, 0, time.UTC), tt.T())
+	assert.True(t, NullTimeOfDay{}.On(DateFromString("2019-07-22"), time.UTC).T().IsZero())
+}
+
+func TestNullTimeOfDayJSON(t *testing.T) {
+	str := struct {
+		Open  NullTimeOfDay `json:"open"`
+		Close NullTimeOfDay `json:"close"`
+	}{}
+	assert.NoError(t, json.Unmarshal([]byte(`{"open":"08:00:00","close":null}`), &str))
+	assert.Equal(t, TimeOfDay(8, 0, 0, 0), str.Open)
+	bb, err := json.Marshal(str)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"open":"08:00:00","close":null}`, string(bb))
+}
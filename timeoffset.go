@@ -0,0 +1,189 @@
+package sqltypes
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NullTimeOffset is a timestamp that keeps the UTC offset it was written with
+// (Postgres timestamptz text, SQL Server datetimeoffset). The zero value is NULL.
+type NullTimeOffset struct {
+	// UTC is the instant, always in UTC
+	UTC time.Time
+	// Offset is the original offset in seconds east of UTC
+	Offset int
+}
+
+const timeOffsetLayout = "2006-01-02 15:04:05.999999999-07:00"
+
+// T returns the time in a fixed zone with the original offset.
+func (t NullTimeOffset) T() time.Time {
+	if t.UTC.IsZero() {
+		return time.Time{}
+	}
+	return t.UTC.In(time.FixedZone(formatOffset(t.Offset), t.Offset))
+}
+
+// In returns the time in loc.
+func (t NullTimeOffset) In(loc *time.Location) time.Time {
+	return t.UTC.In(loc)
+}
+
+// IsZero reports whether t is NULL.
+func (t NullTimeOffset) IsZero() bool {
+	return t.UTC.IsZero()
+}
+
+// String returns the time as "2006-01-02 15:04:05.999999999-07:00"
+// or "" if NULL.
+func (t NullTimeOffset) String() string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.T().Format(timeOffsetLayout)
+}
+
+// Scan implements the Scanner interface.
+func (t *NullTimeOffset) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*t = NullTimeOffset{}
+		return nil
+	case time.Time:
+		*t = TimeOffset(v)
+		return nil
+	case []byte:
+		return t.parse(string(v))
+	case string:
+		return t.parse(v)
+	}
+	return fmt.Errorf("unsupported Scan, storing driver.Value type %T into type %T", value, t)
+}
+
+// Value implements the driver Valuer interface.
+func (t NullTimeOffset) Value() (driver.Value, error) {
+	if t.IsZero() {
+		return nil, nil
+	}
+	return t.String(), nil
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (t NullTimeOffset) MarshalText() ([]byte, error) {
+	if t.IsZero() {
+		return []byte{}, nil
+	}
+	return []byte(t.T().Format(time.RFC3339Nano)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (t *NullTimeOffset) UnmarshalText(v []byte) error {
+	if len(v) == 0 {
+		*t = NullTimeOffset{}
+		return nil
+	}
+	return t.parse(string(v))
+}
+
+// MarshalJSON implements json.Marshaler
+func (t NullTimeOffset) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return []byte(strconv.Quote(t.T().Format(time.RFC3339Nano))), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (t *NullTimeOffset) UnmarshalJSON(v []byte) error {
+	if len(v) == 0 || string(v) == "null" {
+		*t = NullTimeOffset{}
+		return nil
+	}
+	s, err := strconv.Unquote(string(v))
+	if err != nil {
+		return fmt.Errorf("invalid time offset %s", string(v))
+	}
+	return t.UnmarshalText([]byte(s))
+}
+
+// parse accepts "2006-01-02[ T]15:04:05[.fffffffff][ ](Z|±hh[[:]mm[[:]ss]])"
+func (t *NullTimeOffset) parse(s string) error {
+	s = strings.TrimSpace(s)
+	if len(s) < len("2006-01-02 15:04:05") {
+		return fmt.Errorf("invalid time offset '%s'", s)
+	}
+	var offset int
+	var dt string
+	if s[len(s)-1] == 'Z' || s[len(s)-1] == 'z' {
+		dt = s[:len(s)-1]
+	} else {
+		// the offset sign can't be part of the date
+		i := strings.LastIndexAny(s[10:], "+-")
+		if i < 0 {
+			return fmt.Errorf("invalid time offset '%s': missing UTC offset", s)
+		}
+		i += 10
+		o, err := parseOffset(s[i:])
+		if err != nil {
+			return fmt.Errorf("invalid time offset '%s': %v", s, err)
+		}
+		offset = o
+		dt = s[:i]
+	}
+	dt = strings.Replace(strings.TrimSpace(dt), "T", " ", 1)
+	tt, err := time.Parse("2006-01-02 15:04:05", dt)
+	if err != nil {
+		return fmt.Errorf("invalid time offset '%s': %v", s, err)
+	}
+	*t = NullTimeOffset{
+		UTC:    tt.Add(-time.Duration(offset) * time.Second),
+		Offset: offset,
+	}
+	return nil
+}
+
+// parseOffset parses ±hh, ±hhmm, ±hh:mm and ±hh:mm:ss into seconds east of UTC
+func parseOffset(s string) (int, error) {
+	if len(s) < 3 || (s[0] != '+' && s[0] != '-') {
+		return 0, fmt.Errorf("invalid offset '%s'", s)
+	}
+	sign := 1
+	if s[0] == '-' {
+		sign = -1
+	}
+	digits := strings.Replace(s[1:], ":", "", -1)
+	if len(digits) != 2 && len(digits) != 4 && len(digits) != 6 {
+		return 0, fmt.Errorf("invalid offset '%s'", s)
+	}
+	secs := 0
+	mul := 3600
+	for i := 0; i < len(digits); i += 2 {
+		n, err := strconv.Atoi(digits[i : i+2])
+		if err != nil || (i > 0 && n > 59) {
+			return 0, fmt.Errorf("invalid offset '%s'", s)
+		}
+		secs += n * mul
+		mul /= 60
+	}
+	if secs > 24*3600 {
+		return 0, fmt.Errorf("invalid offset '%s'", s)
+	}
+	return sign * secs, nil
+}
+
+// formatOffset returns the offset as ±hh:mm (or ±hh:mm:ss)
+func formatOffset(offset int) string {
+	sign := '+'
+	if offset < 0 {
+		sign = '-'
+		offset = -offset
+	}
+	h, m, s := offset/3600, offset/60%60, offset%60
+	if s != 0 {
+		return fmt.Sprintf("%c%02d:%02d:%02d", sign, h, m, s)
+	}
+	return fmt.Sprintf("%c%02d:%02d", sign, h, m)
+}
@@ -0,0 +1,58 @@
+package sqltypes
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNullTimeOffsetScan(t *testing.T) {
+	var to NullTimeOffset
+	assert.NoError(t, to.Scan("2019-07-22 10:30:00.123456-03"))
+	assert.Equal(t, -3*3600, to.Offset)
+	assert.Equal(t, 13, to.UTC.Hour())
+	assert.Equal(t, "2019-07-22 10:30:00.123456-03:00", to.String())
+	//
+	assert.NoError(t, to.Scan([]byte("2019-07-22 10:30:00.1234567 +05:30")))
+	assert.Equal(t, 5*3600+30*60, to.Offset)
+	assert.Equal(t, 5, to.UTC.Hour())
+	assert.Equal(t, 10, to.T().Hour())
+	//
+	assert.NoError(t, to.Scan("2019-07-22T10:30:00Z"))
+	assert.Equal(t, 0, to.Offset)
+	//
+	assert.NoError(t, to.Scan(time.Date(2019, 7, 22, 10, 30, 0, 0, time.FixedZone("", -7200))))
+	assert.Equal(t, -7200, to.Offset)
+	assert.Equal(t, "2019-07-22 10:30:00-02:00", to.String())
+	//
+	assert.Error(t, to.Scan("2019-07-22 10:30:00"))
+	assert.Error(t, to.Scan("2019-07-22 10:30:00+5"))
+	assert.NoError(t, to.Scan(nil))
+	assert.True(t, to.IsZero())
+}
+
+func TestNullTimeOffsetValue(t *testing.T) {
+	to := TimeOffset(time.Date(2019, 7, 22, 10, 30, 0, 0, time.FixedZone("", -3*3600)))
+	v, err := to.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "2019-07-22 10:30:00-03:00", v)
+	v, err = NullTimeOffset{}.Value()
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+	assert.Equal(t, 13, to.In(time.UTC).Hour())
+}
+
+func TestNullTimeOffsetJSON(t *testing.T) {
+	str := struct {
+		A NullTimeOffset `json:"a"`
+		B NullTimeOffset `json:"b"`
+	}{}
+	assert.NoError(t, json.Unmarshal([]byte(`{"a":"2019-07-22T10:30:00+09:00","b":null}`), &str))
+	assert.Equal(t, 9*3600, str.A.Offset)
+	assert.True(t, str.B.IsZero())
+	bb, err := json.Marshal(str)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a":"2019-07-22T10:30:00+09:00","b":null}`, string(bb))
+}
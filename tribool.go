@@ -0,0 +1,149 @@
+package sqltypes
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// NullTriBool is a boolean that keeps NULL as Unknown and follows SQL's
+// three-valued logic. The zero value is TriUnknown.
+type NullTriBool uint8
+
+// NullTriBool values
+const (
+	TriUnknown NullTriBool = iota
+	TriFalse
+	TriTrue
+)
+
+// IsTrue reports whether b is TRUE (SQL "b IS TRUE")
+func (b NullTriBool) IsTrue() bool {
+	return b == TriTrue
+}
+
+// IsFalse reports whether b is FALSE (SQL "b IS FALSE")
+func (b NullTriBool) IsFalse() bool {
+	return b == TriFalse
+}
+
+// IsUnknown reports whether b is NULL (SQL "b IS UNKNOWN")
+func (b NullTriBool) IsUnknown() bool {
+	return b != TriTrue && b != TriFalse
+}
+
+// Bool returns the value of b and whether it is known
+func (b NullTriBool) Bool() (v bool, ok bool) {
+	return b == TriTrue, !b.IsUnknown()
+}
+
+// Not returns NOT b
+func (b NullTriBool) Not() NullTriBool {
+	switch b {
+	case TriTrue:
+		return TriFalse
+	case TriFalse:
+		return TriTrue
+	}
+	return TriUnknown
+}
+
+// And returns b AND o: FALSE if either is FALSE, otherwise UNKNOWN if either
+// is UNKNOWN
+func (b NullTriBool) And(o NullTriBool) NullTriBool {
+	switch {
+	case b == TriFalse || o == TriFalse:
+		return TriFalse
+	case b.IsUnknown() || o.IsUnknown():
+		return TriUnknown
+	}
+	return TriTrue
+}
+
+// Or returns b OR o: TRUE if either is TRUE, otherwise UNKNOWN if either is
+// UNKNOWN
+func (b NullTriBool) Or(o NullTriBool) NullTriBool {
+	switch {
+	case b == TriTrue || o == TriTrue:
+		return TriTrue
+	case b.IsUnknown() || o.IsUnknown():
+		return TriUnknown
+	}
+	return TriFalse
+}
+
+// Implies returns (NOT b) OR o
+func (b NullTriBool) Implies(o NullTriBool) NullTriBool {
+	return b.Not().Or(o)
+}
+
+// Eq returns b = o, which is UNKNOWN if either is UNKNOWN
+func (b NullTriBool) Eq(o NullTriBool) NullTriBool {
+	if b.IsUnknown() || o.IsUnknown() {
+		return TriUnknown
+	}
+	return TriBool(b == o)
+}
+
+func (b NullTriBool) String() string {
+	switch b {
+	case TriTrue:
+		return "TRUE"
+	case TriFalse:
+		return "FALSE"
+	}
+	return "UNKNOWN"
+}
+
+// Scan implements the Scanner interface.
+func (b *NullTriBool) Scan(value interface{}) error {
+	if value == nil {
+		*b = TriUnknown
+		return nil
+	}
+	if v, ok := value.([]byte); ok {
+		value = string(v)
+	}
+	bv, err := driver.Bool.ConvertValue(value)
+	if err != nil {
+		return fmt.Errorf("converting driver.Value type %T (%v) to a NullTriBool: %v", value, value, err)
+	}
+	*b = TriBool(bv.(bool))
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (b NullTriBool) Value() (driver.Value, error) {
+	switch b {
+	case TriTrue:
+		return int64(1), nil
+	case TriFalse:
+		return int64(0), nil
+	}
+	return nil, nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (b NullTriBool) MarshalJSON() ([]byte, error) {
+	switch b {
+	case TriTrue:
+		return []byte("true"), nil
+	case TriFalse:
+		return []byte("false"), nil
+	}
+	return []byte("null"), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (b *NullTriBool) UnmarshalJSON(v []byte) error {
+	switch string(v) {
+	case "true":
+		*b = TriTrue
+	case "false":
+		*b = TriFalse
+	case "null", "":
+		*b = TriUnknown
+	default:
+		return fmt.Errorf("invalid NullTriBool %s", string(v))
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package sqltypes
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNullTriBoolLogic(t *testing.T) {
+	T, F, U := TriTrue, TriFalse, TriUnknown
+	vals := []NullTriBool{T, F, U}
+	// SQL truth tables, indexed like vals
+	and := [3][3]NullTriBool{
+		{T, F, U},
+		{F, F, F},
+		{U, F, U},
+	}
+	or := [3][3]NullTriBool{
+		{T, T, T},
+		{T, F, U},
+		{T, U, U},
+	}
+	implies := [3][3]NullTriBool{
+		{T, F, U},
+		{T, T, T},
+		{T, U, U},
+	}
+	eq := [3][3]NullTriBool{
+		{T, F, U},
+		{F, T, U},
+		{U, U, U},
+	}
+	for i, a := range vals {
+		for j, b := range vals {
+			assert.Equal(t, and[i][j], a.And(b), "%v AND %v", a, b)
+			assert.Equal(t, or[i][j], a.Or(b), "%v OR %v", a, b)
+			assert.Equal(t, implies[i][j], a.Implies(b), "%v IMPLIES %v", a, b)
+			assert.Equal(t, eq[i][j], a.Eq(b), "%v = %v", a, b)
+		}
+	}
+	assert.Equal(t, F, T.Not())
+	assert.Equal(t, T, F.Not())
+	assert.Equal(t, U, U.Not())
+	assert.True(t, T.IsTrue())
+	assert.True(t, F.IsFalse())
+	assert.False(t, U.IsTrue())
+	assert.False(t, U.IsFalse())
+	_, ok := U.Bool()
+	assert.False(t, ok)
+}
+
+func TestNullTriBoolScan(t *testing.T) {
+	var b NullTriBool
+	assert.NoError(t, b.Scan(int64(1)))
+	assert.Equal(t, TriTrue, b)
+	assert.NoError(t, b.Scan(false))
+	assert.Equal(t, TriFalse, b)
+	assert.NoError(t, b.Scan([]byte("1")))
+	assert.Equal(t, TriTrue, b)
+	assert.NoError(t, b.Scan(nil))
+	assert.Equal(t, TriUnknown, b)
+	assert.Error(t, b.Scan(int64(2)))
+	v, err := TriFalse.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), v)
+	v, err = TriUnknown.Value()
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+}
+
+func TestNullTriBoolJSON(t *testing.T) {
+	m := make(map[string]NullTriBool)
+	assert.NoError(t, json.Unmarshal([]byte(`{"a":true,"b":false,"c":null}`), &m))
+	assert.Equal(t, TriTrue, m["a"])
+	assert.Equal(t, TriFalse, m["b"])
+	assert.Equal(t, TriUnknown, m["c"])
+	bb, err := json.Marshal(m)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a":true,"b":false,"c":null}`, string(bb))
+	assert.Error(t, json.Unmarshal([]byte(`{"a":1}`), &m))
+}
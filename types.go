@@ -14,20 +14,48 @@ import (
 // NullBool is a bool that can be NULL (from DB)
 type NullBool bool
 
-// Scan implements the Scanner interface.
-func (n *NullBool) Scan(value interface{}) error {
-	if value == nil {
-		*n = false
-		return nil
+// scanBool converts the representations of a boolean found in databases:
+// native bool, integers (non zero is true), 'Y'/'N', 'T'/'F', "yes"/"no",
+// "true"/"false" and "on"/"off". NULL is false.
+func scanBool(value interface{}) (bool, error) {
+	switch v := value.(type) {
+	case nil:
+		return false, nil
+	case bool:
+		return v, nil
+	case []byte:
+		return parseBool(string(v))
+	case string:
+		return parseBool(v)
 	}
-
 	ni64 := sql.NullInt64{}
-	err := ni64.Scan(value)
+	if err := ni64.Scan(value); err != nil {
+		return false, err
+	}
+	return ni64.Int64 != 0, nil
+}
+
+func parseBool(s string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "1", "y", "yes", "t", "true", "on":
+		return true, nil
+	case "", "0", "n", "no", "f", "false", "off":
+		return false, nil
+	}
+	i, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
 	if err != nil {
-		return err
+		return false, fmt.Errorf("converting driver.Value type string (%q) to a bool: invalid syntax", s)
 	}
+	return i != 0, nil
+}
 
-	*n = NullBool(ni64.Int64 != 0)
+// Scan implements the Scanner interface.
+func (n *NullBool) Scan(value interface{}) error {
+	v, err := scanBool(value)
+	if err != nil {
+		return err
+	}
+	*n = NullBool(v)
 	return nil
 }
 
@@ -39,6 +67,66 @@ func (n NullBool) Value() (driver.Value, error) {
 	return int64(1), nil
 }
 
+// NullBoolYN is a NullBool stored as 'Y'/'N' (char(1) columns)
+type NullBoolYN bool
+
+// Scan implements the Scanner interface.
+func (n *NullBoolYN) Scan(value interface{}) error {
+	v, err := scanBool(value)
+	if err != nil {
+		return err
+	}
+	*n = NullBoolYN(v)
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (n NullBoolYN) Value() (driver.Value, error) {
+	if n == false {
+		return "N", nil
+	}
+	return "Y", nil
+}
+
+// NullBoolTF is a NullBool stored as 'T'/'F' (char(1) columns)
+type NullBoolTF bool
+
+// Scan implements the Scanner interface.
+func (n *NullBoolTF) Scan(value interface{}) error {
+	v, err := scanBool(value)
+	if err != nil {
+		return err
+	}
+	*n = NullBoolTF(v)
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (n NullBoolTF) Value() (driver.Value, error) {
+	if n == false {
+		return "F", nil
+	}
+	return "T", nil
+}
+
+// NullBoolNative is a NullBool stored as a native BOOLEAN
+type NullBoolNative bool
+
+// Scan implements the Scanner interface.
+func (n *NullBoolNative) Scan(value interface{}) error {
+	v, err := scanBool(value)
+	if err != nil {
+		return err
+	}
+	*n = NullBoolNative(v)
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (n NullBoolNative) Value() (driver.Value, error) {
+	return bool(n), nil
+}
+
 // NullInt0 is a normal int (0 = nil)
 type NullInt0 int
 
@@ -202,9 +290,9 @@ func (t *NullTime) Scan(value interface{}) error {
 	var t9 time.Time
 	switch v := value.(type) {
 	case []byte:
-		t9, e9 = time.Parse("2006-01-02 15:04:05", string(v))
+		t9, e9 = parseRangeTime(string(v))
 	case string:
-		t9, e9 = time.Parse("2006-01-02 15:04:05", v)
+		t9, e9 = parseRangeTime(v)
 	}
 	if e9 == nil {
 		*t = NullTime(t9)
@@ -246,7 +334,8 @@ func (n NullTime) MarshalJSON() ([]byte, error) {
 	return t.MarshalJSON()
 }
 
-//
+// NullDecimal is a decimal.Decimal. It is never written as NULL and Scan(nil)
+// keeps the current value; use NullDecimalOpt to track NULL.
 type NullDecimal decimal.Decimal
 
 func (d NullDecimal) D() decimal.Decimal {
@@ -303,36 +392,30 @@ func (n NullDecimal) MarshalJSON() ([]byte, error) {
 	return t.MarshalJSON()
 }
 
+// DecimalFromString parses s guessing the decimal separator from the last
+// '.' or ','. It returns zero if s is invalid; use ParseDecimal to get the
+// error.
 func DecimalFromString(s string) decimal.Decimal {
-	dotI := strings.LastIndex(s, ".")
-	comI := strings.LastIndex(s, ",")
-	if dotI >= 0 && dotI > comI {
-		s = strings.Replace(s, ",", "", -1)
-	} else if comI >= 0 && comI > dotI {
-		s = strings.Replace(s, ".", "", -1)
-		s = strings.Replace(s, ",", ".", -1)
-	}
-	d, _ := decimal.NewFromString(s)
+	l := &looseDotLocale
+	if strings.LastIndex(s, ",") > strings.LastIndex(s, ".") {
+		l = &looseCommaLocale
+	}
+	d, _ := l.parse(s)
 	return d
 }
 
-// NullFloat64 is a float64 with the 0 value being nil (on sending to sql)
+// NullFloat64 is a float64 with the 0 value being nil (on sending to sql).
+// NaN and infinities are written as they are and marshaled as JSON null; use
+// NullFloat64Opt to choose.
 type NullFloat64 float64
 
 // Scan implements the Scanner interface.
 func (n *NullFloat64) Scan(value interface{}) error {
-	if value == nil {
-		*n = 0
-		return nil
-	}
-
-	nf64 := sql.NullFloat64{}
-	err := nf64.Scan(value)
+	f, _, err := scanFloat(value, 64, false)
 	if err != nil {
 		return err
 	}
-
-	*n = NullFloat64(nf64.Float64)
+	*n = NullFloat64(f)
 	return nil
 }
 
@@ -341,60 +424,134 @@ func (n NullFloat64) Value() (driver.Value, error) {
 	if n == 0 {
 		return nil, nil
 	}
-	return float64(n), nil
+	return floatValue(float64(n), NonFinitePassThrough)
+}
+
+// MarshalJSON implements json.Marshaler
+func (n NullFloat64) MarshalJSON() ([]byte, error) {
+	return marshalFloat(float64(n), 64, false)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (n *NullFloat64) UnmarshalJSON(v []byte) error {
+	if len(v) == 0 || string(v) == "null" {
+		return nil
+	}
+	f, err := unmarshalFloat(v, 64, false)
+	if err != nil {
+		return err
+	}
+	*n = NullFloat64(f)
+	return nil
 }
 
 //
 //
 
-type NullDate string
+// NullDate is a civil date (year, month, day) for SQL DATE columns, packed
+// into 32 bits. The zero value is NULL.
+type NullDate struct {
+	v uint32 // year<<9 | month<<5 | day
+}
 
-func (d NullDate) YMD() (year, month, day int) {
-	ds := strings.Split(string(d), "-")
-	if len(ds) != 3 {
-		return 0, 0, 0
+func packDate(year, month, day int) NullDate {
+	return NullDate{uint32(year)<<9 | uint32(month)<<5 | uint32(day)}
+}
+
+// isValidDate reports whether year-month-day exists in the calendar
+func isValidDate(year, month, day int) bool {
+	if year < 1 || year > 9999 || month < 1 || month > 12 || day < 1 {
+		return false
 	}
-	year, _ = strconv.Atoi(ds[0])
-	month, _ = strconv.Atoi(ds[1])
-	day, _ = strconv.Atoi(ds[2])
-	return
+	return day <= daysIn(year, time.Month(month))
 }
 
+func daysIn(year int, month time.Month) int {
+	switch month {
+	case time.February:
+		if isLeapYear(year) {
+			return 29
+		}
+		return 28
+	case time.April, time.June, time.September, time.November:
+		return 30
+	}
+	return 31
+}
+
+// YMD returns the year, month and day (0, 0, 0 if NULL)
+func (d NullDate) YMD() (year, month, day int) {
+	return int(d.v >> 9), int(d.v >> 5 & 15), int(d.v & 31)
+}
+
+// T returns the date at midnight UTC (the zero time if NULL)
 func (d NullDate) T() time.Time {
+	if d.IsZero() {
+		return time.Time{}
+	}
 	yy, mm, dd := d.YMD()
 	return time.Date(yy, time.Month(mm), dd, 0, 0, 0, 0, time.UTC)
 }
 
+// IsZero reports whether d is NULL
 func (d NullDate) IsZero() bool {
+	return d.v == 0
+}
+
+// String returns the date as "2006-01-02" or "" if NULL
+func (d NullDate) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return string(d.appendISO(make([]byte, 0, 10)))
+}
+
+// appendISO appends the date as "2006-01-02"
+func (d NullDate) appendISO(b []byte) []byte {
 	yy, mm, dd := d.YMD()
-	return yy == 0 || mm == 0 || dd == 0
+	return append(b,
+		byte('0'+yy/1000), byte('0'+yy/100%10), byte('0'+yy/10%10), byte('0'+yy%10), '-',
+		byte('0'+mm/10), byte('0'+mm%10), '-',
+		byte('0'+dd/10), byte('0'+dd%10))
 }
 
+// Scan implements the Scanner interface.
 func (d *NullDate) Scan(value interface{}) error {
-	if value == nil {
+	switch v := value.(type) {
+	case nil:
+		*d = NullDate{}
 		return nil
-	}
-	if v, ok := value.(time.Time); ok {
-		// v = v.UTC()
-		*d = NullDate(v.Format("2006-01-02"))
+	case time.Time:
+		*d = DateFromTime(v)
 		return nil
-	}
-	switch v := value.(type) {
 	case []byte:
 		return d.strscan(string(v))
 	case string:
 		return d.strscan(v)
 	}
-	*d = NullDate("0000-00-00")
-	return nil
+	return fmt.Errorf("unsupported Scan, storing driver.Value type %T into type %T", value, d)
 }
 
+// strscan parses "2006-01-02", ignoring a trailing time part. The MySQL zero
+// date "0000-00-00" is NULL.
 func (d *NullDate) strscan(v string) error {
-	ymd := strings.Split(string(v), "-")
-	if len(ymd) != 3 {
-		return fmt.Errorf("invalid date '%s'", string(v))
+	if len(v) > 10 && (v[10] == ' ' || v[10] == 'T') {
+		v = v[:10]
 	}
-	*d = NullDate(v)
+	if v == "0000-00-00" {
+		*d = NullDate{}
+		return nil
+	}
+	if len(v) != 10 || v[4] != '-' || v[7] != '-' || !isDigits(v[0:4]) || !isDigits(v[5:7]) || !isDigits(v[8:10]) {
+		return fmt.Errorf("invalid date '%s'", v)
+	}
+	yy, e1 := strconv.Atoi(v[0:4])
+	mm, e2 := strconv.Atoi(v[5:7])
+	dd, e3 := strconv.Atoi(v[8:10])
+	if e1 != nil || e2 != nil || e3 != nil || !isValidDate(yy, mm, dd) {
+		return fmt.Errorf("invalid date '%s'", v)
+	}
+	*d = packDate(yy, mm, dd)
 	return nil
 }
 
@@ -403,8 +560,7 @@ func (d NullDate) Value() (driver.Value, error) {
 	if d.IsZero() {
 		return nil, nil
 	}
-	yy, mm, dd := d.YMD()
-	return fmt.Sprintf("%04d-%02d-%02d", yy, mm, dd), nil
+	return d.String(), nil
 }
 
 // UnmarshalJSON implements json.Unmarshaler
@@ -416,9 +572,10 @@ func (d *NullDate) UnmarshalJSON(v []byte) error {
 		return nil
 	}
 	if string(v) == "null" {
+		*d = NullDate{}
 		return nil
 	}
-	if len(v) <= 2 {
+	if len(v) <= 2 || v[0] != '"' || v[len(v)-1] != '"' {
 		return fmt.Errorf("invalid date '%s'", string(v))
 	}
 	str := string(v)
@@ -427,28 +584,22 @@ func (d *NullDate) UnmarshalJSON(v []byte) error {
 }
 
 // MarshalJSON implements json.Marshaler
-func (d *NullDate) MarshalJSON() ([]byte, error) {
-	var yy, mm, dd int
-	if d != nil {
-		yy, mm, dd = d.YMD()
-	}
-	return []byte("\"" + fmt.Sprintf("%04d-%02d-%02d", yy, mm, dd) + "\""), nil
+func (d NullDate) MarshalJSON() ([]byte, error) {
+	b := append(make([]byte, 0, 12), '"')
+	return append(d.appendISO(b), '"'), nil
 }
 
 // Year returns the year
 func (d NullDate) Year() int {
-	yy, _, _ := d.YMD()
-	return yy
+	return int(d.v >> 9)
 }
 
 // Month returns the month
 func (d NullDate) Month() time.Month {
-	_, mm, _ := d.YMD()
-	return time.Month(mm)
+	return time.Month(d.v >> 5 & 15)
 }
 
 // Day returns the day
 func (d NullDate) Day() int {
-	_, _, dd := d.YMD()
-	return dd
+	return int(d.v & 31)
 }
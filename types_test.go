@@ -2,6 +2,9 @@ package sqltypes
 
 import (
 	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -17,13 +20,65 @@ func TestUnmarshal(t *testing.T) {
 	assert.Equal(t, NullString("100"), m["c"])
 }
 
+func TestNullBoolScan(t *testing.T) {
+	var b NullBool
+	for _, v := range []interface{}{true, int64(1), int64(2), "Y", []byte("y"), "T", "true", "TRUE", "yes", "on", []byte("1"), 1.0} {
+		b = false
+		assert.NoError(t, b.Scan(v), "%#v", v)
+		assert.True(t, bool(b), "%#v", v)
+	}
+	for _, v := range []interface{}{nil, false, int64(0), "N", []byte("n"), "F", "false", "no", "off", "0", ""} {
+		b = true
+		assert.NoError(t, b.Scan(v), "%#v", v)
+		assert.False(t, bool(b), "%#v", v)
+	}
+	assert.Error(t, b.Scan("maybe"))
+	v, err := NullBool(true).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), v)
+}
+
+func TestNullBoolEncodings(t *testing.T) {
+	var yn NullBoolYN
+	assert.NoError(t, yn.Scan([]byte("Y")))
+	assert.True(t, bool(yn))
+	v, err := yn.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "Y", v)
+	v, err = NullBoolYN(false).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "N", v)
+	//
+	var tf NullBoolTF
+	assert.NoError(t, tf.Scan(int64(1)))
+	v, err = tf.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "T", v)
+	v, err = NullBoolTF(false).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "F", v)
+	//
+	var nb NullBoolNative
+	assert.NoError(t, nb.Scan("t"))
+	v, err = nb.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, true, v)
+	//
+	bb, err := json.Marshal(struct {
+		A NullBoolYN
+		B NullBoolTF
+	}{true, false})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"A":true,"B":false}`, string(bb))
+}
+
 func TestDecimal(t *testing.T) {
 	assert.Equal(t, DecimalFromString("10.01"), DecimalFromString("10,01"))
 	assert.Equal(t, DecimalFromString("123,010.01"), DecimalFromString("123.010,01"))
 }
 
 func TestNullDate(t *testing.T) {
-	dd := NullDate("2018-03-09")
+	dd := DateFromString("2018-03-09")
 	assert.Equal(t, 2018, dd.Year())
 	//
 	ymdstr, err := dd.Value()
@@ -52,7 +107,7 @@ func TestNullDate(t *testing.T) {
 
 func TestNullDateMarshal(t *testing.T) {
 	var dd NullDate
-	dd = NullDate("1987-03-09")
+	dd = DateFromString("1987-03-09")
 	jj := make(map[string]interface{})
 	jj["date"] = dd
 	jj["a"] = "b"
@@ -64,6 +119,79 @@ func TestNullDateMarshal(t *testing.T) {
 	assert.Equal(t, `{"a":"b","c":1000,"date":"1987-03-09"}`, string(bb))
 }
 
+func TestNullDateValidation(t *testing.T) {
+	var dd NullDate
+	assert.Error(t, dd.Scan("2019-02-31"))
+	assert.Error(t, dd.Scan("abc-de-fg"))
+	assert.Error(t, dd.Scan("2019-7-22"))
+	for _, in := range []string{"+201-01-01", "-201-01-01", "2019-+1-01", "2019-01-+1", " 201-01-01"} {
+		assert.Error(t, dd.Scan(in), in)
+		assert.True(t, DateFromString(in).IsZero(), in)
+	}
+	assert.Error(t, dd.Scan(int64(20190722)))
+	assert.Error(t, json.Unmarshal([]byte(`"2019-13-01"`), &dd))
+	assert.NoError(t, dd.Scan("2020-02-29"))
+	assert.Equal(t, Date(2020, time.February, 29), dd)
+	assert.NoError(t, dd.Scan([]byte("2019-07-22 10:30:00")))
+	assert.Equal(t, "2019-07-22", dd.String())
+	assert.NoError(t, dd.Scan("0000-00-00"))
+	assert.True(t, dd.IsZero())
+	assert.Equal(t, Date(2019, time.March, 3), Date(2019, time.February, 31))
+	assert.True(t, DateFromString("2019-02-31").IsZero())
+	v, err := dd.Value()
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+}
+
+// legacyYMD is the string splitting NullDate used before it was packed
+func legacyYMD(s string) (year, month, day int) {
+	ds := strings.Split(s, "-")
+	if len(ds) != 3 {
+		return 0, 0, 0
+	}
+	year, _ = strconv.Atoi(ds[0])
+	month, _ = strconv.Atoi(ds[1])
+	day, _ = strconv.Atoi(ds[2])
+	return
+}
+
+func BenchmarkNullDateYMD(b *testing.B) {
+	dd := DateFromString("2019-07-22")
+	for i := 0; i < b.N; i++ {
+		dd.YMD()
+	}
+}
+
+func BenchmarkNullDateYMDLegacy(b *testing.B) {
+	s := "2019-07-22"
+	for i := 0; i < b.N; i++ {
+		legacyYMD(s)
+	}
+}
+
+func BenchmarkNullDateValue(b *testing.B) {
+	dd := DateFromString("2019-07-22")
+	for i := 0; i < b.N; i++ {
+		dd.Value()
+	}
+}
+
+func BenchmarkNullDateValueLegacy(b *testing.B) {
+	s := "2019-07-22"
+	for i := 0; i < b.N; i++ {
+		yy, mm, dd := legacyYMD(s)
+		_ = fmt.Sprintf("%04d-%02d-%02d", yy, mm, dd)
+	}
+}
+
+func BenchmarkNullDateScan(b *testing.B) {
+	var dd NullDate
+	v := []byte("2019-07-22")
+	for i := 0; i < b.N; i++ {
+		dd.Scan(v)
+	}
+}
+
 type ntmap struct {
 	Ts map[string]ntt
 }
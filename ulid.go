@@ -0,0 +1,198 @@
+package sqltypes
+
+import (
+	"crypto/rand"
+	"database/sql/driver"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// crockford is the Crockford base32 alphabet of ULIDs
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+var crockfordDec [256]byte
+
+func init() {
+	for i := range crockfordDec {
+		crockfordDec[i] = 0xff
+	}
+	for i := 0; i < len(crockford); i++ {
+		crockfordDec[crockford[i]] = byte(i)
+		crockfordDec[crockford[i]|0x20] = byte(i)
+	}
+	for _, c := range "iIlL" {
+		crockfordDec[c] = 1
+	}
+	for _, c := range "oO" {
+		crockfordDec[c] = 0
+	}
+}
+
+// NullULID is a ULID: a 48 bit millisecond timestamp followed by 80 random
+// bits, written as 26 Crockford base32 characters. Scan reads the text and
+// the 16 byte binary form; Value writes the text or, if Binary is set, the
+// 16 bytes. Valid = false is NULL.
+type NullULID struct {
+	ULID   [16]byte
+	Valid  bool
+	Binary bool
+}
+
+// ParseULID parses a ULID in text form. It is case insensitive and accepts
+// I and L for 1 and O for 0.
+func ParseULID(s string) (NullULID, error) {
+	var u NullULID
+	if len(s) != 26 || crockfordDec[s[0]] > 7 {
+		return u, fmt.Errorf("invalid ULID '%s'", s)
+	}
+	// 26 characters are 130 bits; the first two are always zero
+	var hi uint64 // top 50 bits
+	var lo uint64 // bottom 80 bits are hi's low 16 + lo
+	for i := 0; i < 26; i++ {
+		v := crockfordDec[s[i]]
+		if v == 0xff {
+			return u, fmt.Errorf("invalid ULID '%s'", s)
+		}
+		hi = hi<<5 | lo>>59
+		lo = lo<<5 | uint64(v)
+	}
+	binary.BigEndian.PutUint64(u.ULID[0:], hi)
+	binary.BigEndian.PutUint64(u.ULID[8:], lo)
+	u.Valid = true
+	return u, nil
+}
+
+// String returns the ULID in Crockford base32 or "" if NULL
+func (u NullULID) String() string {
+	if !u.Valid {
+		return ""
+	}
+	hi := binary.BigEndian.Uint64(u.ULID[0:])
+	lo := binary.BigEndian.Uint64(u.ULID[8:])
+	var b [26]byte
+	for i := 25; i >= 0; i-- {
+		b[i] = crockford[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(b[:])
+}
+
+// Time returns the timestamp of the ULID
+func (u NullULID) Time() NullTime {
+	if !u.Valid {
+		return NullTime{}
+	}
+	ms := int64(binary.BigEndian.Uint64(u.ULID[0:]) >> 16)
+	return NullTime(time.Unix(ms/1000, ms%1000*int64(time.Millisecond)).UTC())
+}
+
+// ULIDGenerator generates monotonic ULIDs: within the same millisecond the
+// random part is incremented. It is safe for concurrent use.
+type ULIDGenerator struct {
+	mu   sync.Mutex
+	ms   uint64
+	last [10]byte
+}
+
+var defaultULID ULIDGenerator
+
+// NewULID returns a ULID from the package generator
+func NewULID() (NullULID, error) {
+	return defaultULID.New()
+}
+
+// New returns a ULID greater than the ones generated before
+func (g *ULIDGenerator) New() (NullULID, error) {
+	var u NullULID
+	ms := uint64(time.Now().UnixNano() / int64(time.Millisecond))
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if ms > g.ms {
+		if _, err := rand.Read(g.last[:]); err != nil {
+			return u, err
+		}
+		g.ms = ms
+	} else {
+		// same millisecond (or the clock went back): increment
+		i := len(g.last) - 1
+		for ; i >= 0; i-- {
+			g.last[i]++
+			if g.last[i] != 0 {
+				break
+			}
+		}
+		if i < 0 {
+			return u, fmt.Errorf("ULID overflow in millisecond %d", g.ms)
+		}
+	}
+	binary.BigEndian.PutUint64(u.ULID[0:], g.ms<<16)
+	copy(u.ULID[6:], g.last[:])
+	u.Valid = true
+	return u, nil
+}
+
+// Scan implements the Scanner interface.
+func (u *NullULID) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		u.ULID, u.Valid = [16]byte{}, false
+		return nil
+	case []byte:
+		if len(v) == 16 {
+			copy(u.ULID[:], v)
+			u.Valid = true
+			return nil
+		}
+		return u.parse(string(v))
+	case string:
+		return u.parse(v)
+	}
+	return fmt.Errorf("unsupported Scan, storing driver.Value type %T into type %T", value, u)
+}
+
+func (u *NullULID) parse(s string) error {
+	p, err := ParseULID(s)
+	if err != nil {
+		return err
+	}
+	u.ULID, u.Valid = p.ULID, true
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (u NullULID) Value() (driver.Value, error) {
+	if !u.Valid {
+		return nil, nil
+	}
+	if u.Binary {
+		b := make([]byte, 16)
+		copy(b, u.ULID[:])
+		return b, nil
+	}
+	return u.String(), nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (u NullULID) MarshalJSON() ([]byte, error) {
+	if !u.Valid {
+		return []byte("null"), nil
+	}
+	return []byte(`"` + u.String() + `"`), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (u *NullULID) UnmarshalJSON(v []byte) error {
+	if len(v) == 0 || string(v) == "null" {
+		u.ULID, u.Valid = [16]byte{}, false
+		return nil
+	}
+	s, err := strconv.Unquote(string(v))
+	if err != nil {
+		return fmt.Errorf("invalid ULID %s", string(v))
+	}
+	return u.parse(s)
+}
@@ -0,0 +1,98 @@
+package sqltypes
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseULID(t *testing.T) {
+	u, err := ParseULID("01ARYZ6S41TSV4RRFFQ69G5FAV")
+	assert.NoError(t, err)
+	assert.Equal(t, "01563df36481d6764c61efb99302bd5b", hex.EncodeToString(u.ULID[:]))
+	assert.Equal(t, "01ARYZ6S41TSV4RRFFQ69G5FAV", u.String())
+	assert.Equal(t, int64(1469918176385), u.Time().T().UnixNano()/int64(time.Millisecond))
+	// lower case and aliases
+	l, err := ParseULID("01aryz6s4ltsv4rrffq69g5fav")
+	assert.NoError(t, err)
+	assert.Equal(t, u.ULID, l.ULID)
+	_, err = ParseULID("7ZZZZZZZZZZZZZZZZZZZZZZZZZ")
+	assert.NoError(t, err)
+	for _, s := range []string{"", "01ARYZ6S41TSV4RRFFQ69G5FA", "8ZZZZZZZZZZZZZZZZZZZZZZZZZ", "01ARYZ6S41TSV4RRFFQ69G5FAU"} {
+		_, err = ParseULID(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestNullULIDScanValue(t *testing.T) {
+	u, _ := ParseULID("01ARYZ6S41TSV4RRFFQ69G5FAV")
+	v, err := u.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "01ARYZ6S41TSV4RRFFQ69G5FAV", v)
+	u.Binary = true
+	v, err = u.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, u.ULID[:], v)
+	//
+	var n NullULID
+	assert.NoError(t, n.Scan(v))
+	assert.Equal(t, u.ULID, n.ULID)
+	assert.NoError(t, n.Scan([]byte("01ARYZ6S41TSV4RRFFQ69G5FAV")))
+	assert.Equal(t, u.ULID, n.ULID)
+	assert.NoError(t, n.Scan(nil))
+	assert.False(t, n.Valid)
+	v, err = n.Value()
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+	assert.Error(t, n.Scan(int64(1)))
+	//
+	b, err := json.Marshal(struct{ A, B NullULID }{A: u})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"A":"01ARYZ6S41TSV4RRFFQ69G5FAV","B":null}`, string(b))
+	var s struct{ A, B NullULID }
+	assert.NoError(t, json.Unmarshal(b, &s))
+	assert.Equal(t, u.ULID, s.A.ULID)
+	assert.False(t, s.B.Valid)
+}
+
+func TestULIDGenerator(t *testing.T) {
+	before := time.Now().Truncate(time.Millisecond)
+	var g ULIDGenerator
+	const workers, each = 8, 1000
+	ids := make(chan NullULID, workers*each)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < each; i++ {
+				u, err := g.New()
+				assert.NoError(t, err)
+				ids <- u
+			}
+		}()
+	}
+	wg.Wait()
+	close(ids)
+	seen := make(map[[16]byte]bool)
+	for u := range ids {
+		assert.False(t, seen[u.ULID])
+		seen[u.ULID] = true
+		assert.False(t, u.Time().T().Before(before))
+	}
+	assert.Len(t, seen, workers*each)
+	// sequential IDs are increasing
+	prev, _ := NewULID()
+	for i := 0; i < 1000; i++ {
+		u, err := NewULID()
+		assert.NoError(t, err)
+		if !assert.True(t, prev.String() < u.String()) {
+			break
+		}
+		prev = u
+	}
+}
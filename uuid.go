@@ -0,0 +1,245 @@
+package sqltypes
+
+import (
+	"crypto/rand"
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// UUIDLayout is how a NullUUID is stored
+type UUIDLayout int
+
+const (
+	// UUIDText is the canonical text form, for CHAR(36) and Postgres uuid
+	// columns
+	UUIDText UUIDLayout = iota
+	// UUIDBinary is the 16 bytes in RFC 4122 order, for BINARY(16) columns
+	UUIDBinary
+	// UUIDBinarySwap is the order of MySQL UUID_TO_BIN(uuid, 1): the time
+	// high and time low fields are swapped so v1 UUIDs sort by time
+	UUIDBinarySwap
+	// UUIDMSSQL is the mixed-endian order of SQL Server uniqueidentifier:
+	// the first three fields are little-endian
+	UUIDMSSQL
+)
+
+// NullUUID is a UUID. Scan reads text (with or without dashes, braces or a
+// "urn:uuid:" prefix) and 16 byte values in Layout; Value writes Layout.
+// Valid = false is NULL.
+type NullUUID struct {
+	UUID   [16]byte
+	Valid  bool
+	Layout UUIDLayout
+}
+
+// ParseUUID parses a UUID in text form
+func ParseUUID(s string) (NullUUID, error) {
+	var u NullUUID
+	t := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(s)), "urn:uuid:")
+	if len(t) == 38 && t[0] == '{' && t[37] == '}' {
+		t = t[1:37]
+	}
+	switch len(t) {
+	case 36:
+		if t[8] != '-' || t[13] != '-' || t[18] != '-' || t[23] != '-' {
+			return u, fmt.Errorf("invalid UUID '%s'", s)
+		}
+		t = t[:8] + t[9:13] + t[14:18] + t[19:23] + t[24:]
+	case 32:
+	default:
+		return u, fmt.Errorf("invalid UUID '%s'", s)
+	}
+	if _, err := hex.Decode(u.UUID[:], []byte(t)); err != nil {
+		return u, fmt.Errorf("invalid UUID '%s'", s)
+	}
+	u.Valid = true
+	return u, nil
+}
+
+// NewUUIDv4 returns a random UUID
+func NewUUIDv4() (NullUUID, error) {
+	var u NullUUID
+	if _, err := rand.Read(u.UUID[:]); err != nil {
+		return u, err
+	}
+	u.UUID[6] = u.UUID[6]&0x0f | 0x40
+	u.UUID[8] = u.UUID[8]&0x3f | 0x80
+	u.Valid = true
+	return u, nil
+}
+
+var uuidv7 struct {
+	sync.Mutex
+	ms  int64
+	seq uint16
+}
+
+// NewUUIDv7 returns a time ordered UUID (RFC 9562): a millisecond timestamp
+// followed by a 12 bit counter and random bits. UUIDs generated by the
+// process are strictly increasing.
+func NewUUIDv7() (NullUUID, error) {
+	var u NullUUID
+	if _, err := rand.Read(u.UUID[6:]); err != nil {
+		return u, err
+	}
+	ms := time.Now().UnixNano() / int64(time.Millisecond)
+	uuidv7.Lock()
+	if ms > uuidv7.ms {
+		// start the counter in the lower half, leaving room to increment
+		uuidv7.ms, uuidv7.seq = ms, binary.BigEndian.Uint16(u.UUID[6:])&0x7ff
+	} else {
+		uuidv7.seq++
+		if uuidv7.seq > 0xfff {
+			uuidv7.ms, uuidv7.seq = uuidv7.ms+1, 0
+		}
+	}
+	ms, seq := uuidv7.ms, uuidv7.seq
+	uuidv7.Unlock()
+	binary.BigEndian.PutUint16(u.UUID[4:], uint16(ms))
+	binary.BigEndian.PutUint32(u.UUID[0:], uint32(ms>>16))
+	binary.BigEndian.PutUint16(u.UUID[6:], 0x7000|seq)
+	u.UUID[8] = u.UUID[8]&0x3f | 0x80
+	u.Valid = true
+	return u, nil
+}
+
+// Version returns the version of the UUID (4, 7...) or 0 if NULL
+func (u NullUUID) Version() int {
+	if !u.Valid {
+		return 0
+	}
+	return int(u.UUID[6] >> 4)
+}
+
+// Time returns the timestamp of a v7 UUID; it is zero for other versions
+func (u NullUUID) Time() NullTime {
+	if u.Version() != 7 {
+		return NullTime{}
+	}
+	ms := int64(binary.BigEndian.Uint32(u.UUID[0:]))<<16 | int64(binary.BigEndian.Uint16(u.UUID[4:]))
+	return NullTime(time.Unix(ms/1000, ms%1000*int64(time.Millisecond)).UTC())
+}
+
+// String returns the canonical text form or "" if NULL
+func (u NullUUID) String() string {
+	if !u.Valid {
+		return ""
+	}
+	var b [36]byte
+	hex.Encode(b[0:8], u.UUID[0:4])
+	b[8] = '-'
+	hex.Encode(b[9:13], u.UUID[4:6])
+	b[13] = '-'
+	hex.Encode(b[14:18], u.UUID[6:8])
+	b[18] = '-'
+	hex.Encode(b[19:23], u.UUID[8:10])
+	b[23] = '-'
+	hex.Encode(b[24:], u.UUID[10:])
+	return string(b[:])
+}
+
+// Bytes returns the 16 bytes of the UUID in layout
+func (u NullUUID) Bytes(layout UUIDLayout) []byte {
+	b := make([]byte, 16)
+	s := u.UUID
+	switch layout {
+	case UUIDBinarySwap:
+		copy(b[0:2], s[6:8])
+		copy(b[2:4], s[4:6])
+		copy(b[4:8], s[0:4])
+		copy(b[8:], s[8:])
+	case UUIDMSSQL:
+		b[0], b[1], b[2], b[3] = s[3], s[2], s[1], s[0]
+		b[4], b[5] = s[5], s[4]
+		b[6], b[7] = s[7], s[6]
+		copy(b[8:], s[8:])
+	default:
+		copy(b, s[:])
+	}
+	return b
+}
+
+// uuidFromBytes is the inverse of Bytes
+func uuidFromBytes(b []byte, layout UUIDLayout) [16]byte {
+	var s [16]byte
+	switch layout {
+	case UUIDBinarySwap:
+		copy(s[6:8], b[0:2])
+		copy(s[4:6], b[2:4])
+		copy(s[0:4], b[4:8])
+		copy(s[8:], b[8:])
+	case UUIDMSSQL:
+		s[0], s[1], s[2], s[3] = b[3], b[2], b[1], b[0]
+		s[4], s[5] = b[5], b[4]
+		s[6], s[7] = b[7], b[6]
+		copy(s[8:], b[8:])
+	default:
+		copy(s[:], b)
+	}
+	return s
+}
+
+// Scan implements the Scanner interface.
+func (u *NullUUID) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		u.UUID, u.Valid = [16]byte{}, false
+		return nil
+	case []byte:
+		if len(v) == 16 {
+			u.UUID, u.Valid = uuidFromBytes(v, u.Layout), true
+			return nil
+		}
+		return u.parse(string(v))
+	case string:
+		return u.parse(v)
+	}
+	return fmt.Errorf("unsupported Scan, storing driver.Value type %T into type %T", value, u)
+}
+
+func (u *NullUUID) parse(s string) error {
+	p, err := ParseUUID(s)
+	if err != nil {
+		return err
+	}
+	u.UUID, u.Valid = p.UUID, true
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (u NullUUID) Value() (driver.Value, error) {
+	if !u.Valid {
+		return nil, nil
+	}
+	if u.Layout == UUIDText {
+		return u.String(), nil
+	}
+	return u.Bytes(u.Layout), nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (u NullUUID) MarshalJSON() ([]byte, error) {
+	if !u.Valid {
+		return []byte("null"), nil
+	}
+	return []byte(`"` + u.String() + `"`), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (u *NullUUID) UnmarshalJSON(v []byte) error {
+	if len(v) == 0 || string(v) == "null" {
+		u.UUID, u.Valid = [16]byte{}, false
+		return nil
+	}
+	s, err := strconv.Unquote(string(v))
+	if err != nil {
+		return fmt.Errorf("invalid UUID %s", string(v))
+	}
+	return u.parse(s)
+}
@@ -0,0 +1,110 @@
+package sqltypes
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseUUID(t *testing.T) {
+	const canon = "6ccd780c-baba-1026-9564-5b8c656024db"
+	for _, s := range []string{
+		canon,
+		"6CCD780C-BABA-1026-9564-5B8C656024DB",
+		"{6ccd780c-baba-1026-9564-5b8c656024db}",
+		"urn:uuid:6ccd780c-baba-1026-9564-5b8c656024db",
+		"6ccd780cbaba102695645b8c656024db",
+	} {
+		u, err := ParseUUID(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, canon, u.String(), s)
+	}
+	for _, s := range []string{"", "6ccd780c-baba-1026-9564-5b8c656024d", "6ccd780c_baba-1026-9564-5b8c656024db", "zccd780c-baba-1026-9564-5b8c656024db"} {
+		_, err := ParseUUID(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestNullUUIDLayouts(t *testing.T) {
+	u, _ := ParseUUID("6ccd780c-baba-1026-9564-5b8c656024db")
+	tests := []struct {
+		layout UUIDLayout
+		hex    string
+	}{
+		{UUIDBinary, "6ccd780cbaba102695645b8c656024db"},
+		// SELECT HEX(UUID_TO_BIN('6ccd780c-baba-1026-9564-5b8c656024db', 1))
+		{UUIDBinarySwap, "1026baba6ccd780c95645b8c656024db"},
+		{UUIDMSSQL, "0c78cd6cbaba261095645b8c656024db"},
+	}
+	for _, tt := range tests {
+		u.Layout = tt.layout
+		v, err := u.Value()
+		assert.NoError(t, err)
+		assert.Equal(t, tt.hex, hex.EncodeToString(v.([]byte)))
+		//
+		n := NullUUID{Layout: tt.layout}
+		assert.NoError(t, n.Scan(v))
+		assert.Equal(t, u.String(), n.String())
+		// text is accepted in every layout
+		assert.NoError(t, n.Scan("6ccd780c-baba-1026-9564-5b8c656024db"))
+		assert.Equal(t, u.UUID, n.UUID)
+	}
+	u.Layout = UUIDText
+	v, err := u.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "6ccd780c-baba-1026-9564-5b8c656024db", v)
+	// SQL Server example from the documentation
+	m := NullUUID{Layout: UUIDMSSQL}
+	b, _ := hex.DecodeString("ff19966f868b11d0b42d00c04fc964ff")
+	assert.NoError(t, m.Scan(b))
+	assert.Equal(t, "6f9619ff-8b86-d011-b42d-00c04fc964ff", m.String())
+	//
+	assert.NoError(t, m.Scan(nil))
+	assert.False(t, m.Valid)
+	v, err = m.Value()
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+	assert.Error(t, m.Scan(int64(1)))
+	assert.Error(t, m.Scan([]byte{1, 2, 3}))
+}
+
+func TestNewUUID(t *testing.T) {
+	u, err := NewUUIDv4()
+	assert.NoError(t, err)
+	assert.Equal(t, 4, u.Version())
+	assert.Equal(t, byte(0x80), u.UUID[8]&0xc0)
+	assert.True(t, u.Time().T().IsZero())
+	//
+	before := time.Now().Truncate(time.Millisecond)
+	prev, err := NewUUIDv7()
+	assert.NoError(t, err)
+	assert.Equal(t, 7, prev.Version())
+	assert.Equal(t, byte(0x80), prev.UUID[8]&0xc0)
+	ts := prev.Time().T()
+	assert.False(t, ts.Before(before))
+	assert.False(t, ts.After(time.Now()))
+	for i := 0; i < 10000; i++ {
+		u, err := NewUUIDv7()
+		assert.NoError(t, err)
+		if !assert.True(t, prev.String() < u.String()) {
+			break
+		}
+		prev = u
+	}
+}
+
+func TestNullUUIDJSON(t *testing.T) {
+	u, _ := ParseUUID("6CCD780C-BABA-1026-9564-5B8C656024DB")
+	u.Layout = UUIDBinary
+	b, err := json.Marshal(struct{ A, B NullUUID }{A: u})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"A":"6ccd780c-baba-1026-9564-5b8c656024db","B":null}`, string(b))
+	var s struct{ A, B NullUUID }
+	assert.NoError(t, json.Unmarshal(b, &s))
+	assert.Equal(t, u.UUID, s.A.UUID)
+	assert.False(t, s.B.Valid)
+	assert.Error(t, json.Unmarshal([]byte(`{"A":"nope"}`), &s))
+}